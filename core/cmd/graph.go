@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/cmd"
+)
+
+var graphFormat string
+
+var graphCmd = &cobra.Command{
+	Use:   "graph <workflow>",
+	Short: "Print a graph of the steps in a workflow available in the current project",
+	Long: `Print a graph of the steps in a workflow available in the current project.
+
+The graph includes the steps of compound steps and called workflows, and shows which steps run in
+parallel, which are services, which use the image of another step, and which use variables produced
+by other steps. It can be printed in Graphviz DOT or Mermaid format.`,
+	Run: func(command *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Println("You must specify a workflow!")
+			fmt.Println()
+			fmt.Println("Try running `sbox graph --help` for help")
+			return
+		}
+
+		err := cmd.Graph(args[0], graphFormat)
+		if err != nil {
+			if os.IsNotExist(err) {
+				fmt.Printf("No workflow named %v", args[0])
+				fmt.Println()
+			} else {
+				fmt.Println(err.Error())
+			}
+		}
+	},
+}
+
+func init() {
+	graphCmd.Flags().StringVarP(&graphFormat, "format", "f", "dot", "Format of the graph, one of: dot, mermaid")
+	RootCmd.AddCommand(graphCmd)
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/cmd"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List past workflow runs in the current project",
+	Long:  `List past workflow runs in the current project, most recent first.`,
+	Run: func(command *cobra.Command, args []string) {
+		err := cmd.History()
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/cmd"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+var imagesOptions cmd.RunOptions
+var imagesOutput string
+
+var imagesCmd = &cobra.Command{
+	Use:   "images",
+	Short: "Save and load the images used by workflows, for machines which can't pull them",
+	Long: `Save and load the images used by workflows, for machines which can't pull them.
+
+Save the images used by a workflow to a tar archive on a machine with access to the registries they
+come from, copy the archive over, and load it on the machine which can't reach the registries.`,
+}
+
+var imagesSaveCmd = &cobra.Command{
+	Use:   "save <workflow> -o <archive>",
+	Short: "Save the images used by a workflow in the current project to a tar archive",
+	Long: `Save the images used by a workflow in the current project to a tar archive.
+
+The images referenced by the steps of the workflow, including the steps of compound steps and called
+workflows, are pulled if they aren't present yet, and saved to the archive. If the workflow has a
+lockfile, the pinned images are saved, and they are tagged with the tags they are pinned for when
+the archive is loaded. The base images of the Dockerfiles of steps are saved too, and
+saving fails if any of them depend on build arguments, as they can't be determined up front.`,
+	Run: func(command *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Println("You must specify a workflow!")
+			fmt.Println()
+			fmt.Println("Try running `sbox images save --help` for help")
+			return
+		}
+
+		if len(imagesOutput) < 1 {
+			fmt.Println("You must specify the archive to save the images to!")
+			fmt.Println()
+			fmt.Println("Try running `sbox images save --help` for help")
+			return
+		}
+
+		err := cmd.SelectBackend(&imagesOptions)
+		if err != nil {
+			log.Errorf("%v", err.Error())
+			os.Exit(1)
+		}
+
+		if imagesOptions.RequiresSandbox() {
+			startKube()
+		}
+
+		err = cmd.SaveImages(args[0], imagesOutput, &imagesOptions)
+		if err != nil {
+			if os.IsNotExist(err) {
+				log.Errorf("No workflow named %v", args[0])
+			} else {
+				log.Errorf("%v", err.Error())
+			}
+
+			os.Exit(1)
+		}
+	},
+}
+
+var imagesLoadCmd = &cobra.Command{
+	Use:   "load <archive>",
+	Short: "Load the images in a tar archive saved by sbox images save",
+	Long: `Load the images in a tar archive saved by sbox images save.
+
+The images are loaded into the Docker daemon of the backend, so that workflows which use them can run
+without pulling them. Images which were saved by digest are tagged with the tags they were pinned for.`,
+	Run: func(command *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Println("You must specify an archive!")
+			fmt.Println()
+			fmt.Println("Try running `sbox images load --help` for help")
+			return
+		}
+
+		err := cmd.SelectBackend(&imagesOptions)
+		if err != nil {
+			log.Errorf("%v", err.Error())
+			os.Exit(1)
+		}
+
+		if imagesOptions.RequiresSandbox() {
+			startKube()
+		}
+
+		err = cmd.LoadImages(args[0], &imagesOptions)
+		if err != nil {
+			log.Errorf("%v", err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	configureKubeStartingCommandFlags(imagesSaveCmd)
+	configureKubeStartingCommandFlags(imagesLoadCmd)
+	imagesCmd.PersistentFlags().StringVar(&imagesOptions.Backend, "backend", "", "Backend whose Docker daemon the images are saved from or loaded into: kube (the default), or docker. Defaults to the contents of .sbox/backend")
+	imagesSaveCmd.Flags().StringVarP(&imagesOutput, "output", "o", "", "Path of the tar archive to save the images to")
+	imagesCmd.AddCommand(imagesSaveCmd)
+	imagesCmd.AddCommand(imagesLoadCmd)
+	RootCmd.AddCommand(imagesCmd)
+}
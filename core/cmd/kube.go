@@ -160,8 +160,8 @@ func startKube() {
 		DisableDriverMounts: viper.GetBool(disableDriverMounts),
 	}
 
-	fmt.Printf("Setting up and starting a local Kubernetes %s cluster...\n", kubernetesVersion)
-	fmt.Println("Starting Sandbox VM...")
+	log.Infof("Setting up and starting a local Kubernetes %s cluster...", kubernetesVersion)
+	log.Infof("Starting Sandbox VM...")
 	var host *host.Host
 	start := func() (err error) {
 		host, err = cluster.StartHost(api, config)
@@ -176,7 +176,7 @@ func startKube() {
 		MaybeReportErrorAndExit(err)
 	}
 
-	fmt.Println("Getting Sandbox VM IP address...")
+	log.Infof("Getting Sandbox VM IP address...")
 	ip, err := host.Driver.GetIP()
 	if err != nil {
 		log.Errorf("Error getting Sandbox VM IP address: %v\n", err)
@@ -193,26 +193,26 @@ func startKube() {
 		ExtraOptions:      extraOptions,
 	}
 
-	fmt.Println("Moving files into single-node Kubernetes cluster...")
+	log.Infof("Moving files into single-node Kubernetes cluster...")
 	if err := cluster.UpdateCluster(host.Driver, kubernetesConfig); err != nil {
 		log.Errorf("Error updating cluster: %v", err)
 		MaybeReportErrorAndExit(err)
 	}
 
-	fmt.Println("Setting up certificates...")
+	log.Infof("Setting up certificates...")
 	if err := cluster.SetupCerts(host.Driver, kubernetesConfig.APIServerName, kubernetesConfig.DNSDomain); err != nil {
 		log.Errorf("Error configuring authentication: %v\n", err)
 		MaybeReportErrorAndExit(err)
 	}
 
-	fmt.Println("Starting single-node Kubernetes cluster components...")
+	log.Infof("Starting single-node Kubernetes cluster components...")
 
 	if err := cluster.StartCluster(api, kubernetesConfig); err != nil {
 		log.Errorf("Error starting cluster: %v\n", err)
 		MaybeReportErrorAndExit(err)
 	}
 
-	fmt.Println("Connecting to single-node Kubernetes cluster...")
+	log.Infof("Connecting to single-node Kubernetes cluster...")
 	kubeHost, err := host.Driver.GetURL()
 	if err != nil {
 		log.Errorf("Error connecting to cluster: %v\n", err)
@@ -220,7 +220,7 @@ func startKube() {
 	kubeHost = strings.Replace(kubeHost, "tcp://", "https://", -1)
 	kubeHost = strings.Replace(kubeHost, ":2376", ":"+strconv.Itoa(pkgutil.APIServerPort), -1)
 
-	fmt.Println("Setting up kubeconfig...")
+	log.Infof("Setting up kubeconfig...")
 	// setup kubeconfig
 
 	kubeConfigEnv := GetKubeConfigPath()
@@ -248,7 +248,7 @@ func startKube() {
 
 	// start 9p server mount
 	if viper.GetBool(createMount) {
-		fmt.Printf("Setting up hostmount on %s...\n", viper.GetString(mountString))
+		log.Infof("Setting up hostmount on %s...", viper.GetString(mountString))
 
 		path := os.Args[0]
 		mountDebugVal := 0
@@ -273,19 +273,19 @@ func startKube() {
 		}
 	}
 
-	fmt.Printf("A local Kubernetes cluster has been started. If you are familiar with Kubernetes and use kubectl, "+
+	log.Infof("A local Kubernetes cluster has been started. If you are familiar with Kubernetes and use kubectl, "+
 		"note that the kubectl context has not been altered. If you want to use kubectl with the cluster that "+
-		"was just started, kubectl will require \"--context=%s\".\n",
+		"was just started, kubectl will require \"--context=%s\".",
 		kubeCfgSetup.ClusterName)
 
 	if config.VMDriver == "none" {
-		fmt.Println(`===================
+		fmt.Fprintln(log.Output(), `===================
 WARNING: IT IS RECOMMENDED NOT TO RUN THE NONE DRIVER ON PERSONAL WORKSTATIONS
 	The 'none' driver will run an insecure kubernetes apiserver as root that may leave the host vulnerable to CSRF attacks
 `)
 
 		if os.Getenv("CHANGE_MINIKUBE_NONE_USER") == "" {
-			fmt.Println(`When using the none driver, the kubectl config and credentials generated will be root owned and will appear in the root home directory.
+			fmt.Fprintln(log.Output(), `When using the none driver, the kubectl config and credentials generated will be root owned and will appear in the root home directory.
 You will need to move the files to the appropriate location and then set the correct permissions.  An example of this is below:
 	sudo mv /root/.kube $HOME/.kube # this will overwrite any config you have.  You may have to append the file contents manually
 	sudo chown -R $USER $HOME/.kube
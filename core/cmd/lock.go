@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/cmd"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+var lockOptions cmd.RunOptions
+
+var lockCmd = &cobra.Command{
+	Use:   "lock <workflow>",
+	Short: "Pin the images used by a workflow in the current project to their current digests",
+	Long: `Pin the images used by a workflow in the current project to their current digests.
+
+The latest versions of the images referenced by the steps of the workflow, including the steps
+of compound steps and called workflows, are pulled, and the digest of each one is written to a
+lockfile next to the workflow (for example, workflows/build.lock). Later runs of the workflow use
+the pinned digests instead of tags like node:latest, which change between runs. Re-run this
+command to update the pinned images.`,
+	Run: func(command *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Println("You must specify a workflow!")
+			fmt.Println()
+			fmt.Println("Try running `sbox lock --help` for help")
+			return
+		}
+
+		err := cmd.SelectBackend(&lockOptions)
+		if err != nil {
+			log.Errorf("%v", err.Error())
+			os.Exit(1)
+		}
+
+		if lockOptions.RequiresSandbox() {
+			startKube()
+		}
+
+		err = cmd.Lock(args[0], &lockOptions)
+		if err != nil {
+			if os.IsNotExist(err) {
+				log.Errorf("No workflow named %v", args[0])
+			} else {
+				log.Errorf("%v", err.Error())
+			}
+
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	configureKubeStartingCommandFlags(lockCmd)
+	lockCmd.Flags().StringVar(&lockOptions.Backend, "backend", "", "Backend whose Docker daemon the images are pulled to: kube (the default), or docker. Defaults to the contents of .sbox/backend")
+	RootCmd.AddCommand(lockCmd)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/cmd"
+)
+
+var followLogs bool
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <run> [step]",
+	Short: "Print the logs of a past workflow run in the current project",
+	Long: `Print the logs of a past workflow run in the current project.
+
+The run can be specified by its ID, a unique prefix of its ID, or "last" for the most
+recent run. If a step is specified, only the logs of that step are printed.`,
+	Run: func(command *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Println("You must specify a run!")
+			fmt.Println()
+			fmt.Println("Try running `sbox logs --help` for help")
+			return
+		}
+
+		var step string
+		if len(args) > 1 {
+			step = args[1]
+		}
+
+		err := cmd.Logs(args[0], step, followLogs)
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	},
+}
+
+func init() {
+	logsCmd.Flags().BoolVarP(&followLogs, "follow", "f", false, "Keep printing new output until the run finishes")
+	RootCmd.AddCommand(logsCmd)
+}
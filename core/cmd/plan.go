@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/cmd"
+)
+
+var planCmd = &cobra.Command{
+	DisableFlagParsing: true,
+	Use:                "plan",
+	Short:              "Show how a workflow in the current project would run",
+	Long: `Show how a workflow in the current project would run, without building or running anything.
+
+This is the same as running the workflow with --dry-run. The build context, Dockerfile, script,
+environment and Kubernetes manifests of each step are printed.`,
+	Run: func(command *cobra.Command, args []string) {
+		if !haveMinArgs(args) {
+			return
+		}
+
+		if args[0] == "--help" || args[0] == "-h" {
+			command.Help()
+			return
+		}
+
+		options := cmd.RunOptions{DryRun: true}
+		args = parseFlags(args, &options)
+		if !haveMinArgs(args) {
+			return
+		}
+
+		runWorkflow(args[0], args[1:], &options)
+	},
+}
+
+func init() {
+	planCmd.Flags().StringP("output", "o", "", "Output format, one of: yaml, json")
+	RootCmd.AddCommand(planCmd)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/cmd"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+var pruneOptions cmd.PruneOptions
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove the images, containers, services and workflow resources generated by workflow runs",
+	Long: `Remove the images, containers, services and workflow resources generated by workflow runs.
+
+Step images, images committed from step containers and stopped step containers are
+removed, along with the anonymous volumes of the containers. Only the artifacts of
+the current project are removed, unless --project or --all is specified. Services
+whose pods no longer exist are removed for every project. The workflow resources of
+runs which finished are removed, as are the ones of runs which are still marked as
+running, because their CLI exited, once they are older than --older-than. With
+--kubeconfig or --context, services and workflow resources are pruned on an existing
+cluster, and images and containers on the Docker daemon configured in the environment.
+
+Images can also be pruned automatically while a workflow is run, once disk usage goes
+above the size specified in .sbox/prune-threshold (for example, 20g). Disk usage is
+checked before step images are built, at most once a minute. When images are built in
+the Sandbox VM, the space used on the disk of the VM which Docker stores images on is
+measured. With the docker backend, or on an existing cluster, the disk of the Docker
+daemon can't be measured, so the threshold is a budget for the total size of generated
+images instead. The oldest images which aren't used by containers, or by the workflow
+being run, are then removed until disk usage is below the threshold.`,
+	Run: func(command *cobra.Command, args []string) {
+		err := cmd.Prune(&pruneOptions)
+		if err != nil {
+			log.Errorf("%v", err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	pruneCmd.Flags().BoolVar(&pruneOptions.All, "all", false, "Prune the artifacts of all projects, including artifacts generated before they were labelled with their project")
+	pruneCmd.Flags().StringVar(&pruneOptions.Backend, "backend", "", "Backend whose artifacts are pruned: kube (the default), or docker. Defaults to the contents of .sbox/backend")
+	pruneCmd.Flags().BoolVar(&pruneOptions.DryRun, "dry-run", false, "Print what would be pruned, without removing anything")
+	pruneCmd.Flags().StringVar(&pruneOptions.Kubeconfig, "kubeconfig", "", "Prune the services and workflow resources on the existing Kubernetes cluster configured in the specified kubeconfig file, instead of the Sandbox VM")
+	pruneCmd.Flags().StringVar(&pruneOptions.KubeContext, "context", "", "Prune the services and workflow resources on the existing Kubernetes cluster of the specified kubeconfig context, instead of the Sandbox VM")
+	pruneCmd.Flags().DurationVar(&pruneOptions.OlderThan, "older-than", 0, "Only prune artifacts created longer ago than the specified duration (for example, 72h)")
+	pruneCmd.Flags().StringVar(&pruneOptions.Project, "project", "", "Prune the artifacts of the project in the specified directory, instead of the current project")
+	RootCmd.AddCommand(pruneCmd)
+}
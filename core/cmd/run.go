@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -11,18 +12,92 @@ import (
 	"github.com/stackfoundation/sandbox/log"
 )
 
-func parseFlags(args []string) []string {
+func parseValueFlag(arg, name string) (string, bool) {
+	if strings.HasPrefix(arg, name+"=") {
+		return arg[len(name)+1:], true
+	}
+
+	return "", false
+}
+
+func parseFlags(args []string, options *cmd.RunOptions) []string {
 	var filtered []string
 	var ignoreNext bool
+	var receiveNext *string
+	var receiveNextList *[]string
 
 	for _, arg := range args {
-		if arg == "-d" || arg == "--debug" {
+		if receiveNext != nil {
+			*receiveNext = arg
+			receiveNext = nil
+		} else if receiveNextList != nil {
+			*receiveNextList = append(*receiveNextList, strings.Split(arg, ",")...)
+			receiveNextList = nil
+		} else if arg == "-d" || arg == "--debug" {
 			log.SetDebug(true)
 			filtered = append(filtered, arg)
 		} else if arg == "--original-command" {
 			ignoreNext = true
 		} else if ignoreNext {
 			ignoreNext = false
+		} else if arg == "--resume" {
+			options.Resume = true
+		} else if arg == "--from" {
+			options.Resume = true
+			receiveNext = &options.From
+		} else if from, ok := parseValueFlag(arg, "--from"); ok {
+			options.Resume = true
+			options.From = from
+		} else if arg == "--only" {
+			receiveNextList = &options.Only
+		} else if only, ok := parseValueFlag(arg, "--only"); ok {
+			options.Only = append(options.Only, strings.Split(only, ",")...)
+		} else if arg == "--skip" {
+			receiveNextList = &options.Skip
+		} else if skip, ok := parseValueFlag(arg, "--skip"); ok {
+			options.Skip = append(options.Skip, strings.Split(skip, ",")...)
+		} else if arg == "--no-services" {
+			options.NoServices = true
+		} else if arg == "--events" {
+			receiveNext = &options.Events
+		} else if events, ok := parseValueFlag(arg, "--events"); ok {
+			options.Events = events
+		} else if arg == "--dry-run" {
+			options.DryRun = true
+		} else if arg == "-o" || arg == "--output" {
+			receiveNext = &options.Output
+		} else if output, ok := parseValueFlag(arg, "--output"); ok {
+			options.Output = output
+		} else if arg == "--report" {
+			receiveNextList = &options.Report
+		} else if report, ok := parseValueFlag(arg, "--report"); ok {
+			options.Report = append(options.Report, strings.Split(report, ",")...)
+		} else if arg == "--watch" {
+			options.Watch = "all"
+		} else if watch, ok := parseValueFlag(arg, "--watch"); ok {
+			options.Watch = watch
+		} else if arg == "--backend" {
+			receiveNext = &options.Backend
+		} else if backend, ok := parseValueFlag(arg, "--backend"); ok {
+			options.Backend = backend
+		} else if arg == "--kubeconfig" {
+			receiveNext = &options.Kubeconfig
+		} else if kubeconfig, ok := parseValueFlag(arg, "--kubeconfig"); ok {
+			options.Kubeconfig = kubeconfig
+		} else if arg == "--context" {
+			receiveNext = &options.KubeContext
+		} else if kubeContext, ok := parseValueFlag(arg, "--context"); ok {
+			options.KubeContext = kubeContext
+		} else if arg == "--registry" {
+			receiveNext = &options.Registry
+		} else if registry, ok := parseValueFlag(arg, "--registry"); ok {
+			options.Registry = registry
+		} else if arg == "--resume-run" {
+			receiveNext = &options.ResumeRun
+		} else if resumeRun, ok := parseValueFlag(arg, "--resume-run"); ok {
+			options.ResumeRun = resumeRun
+		} else if arg == "--timings" {
+			options.Timings = true
 		} else {
 			filtered = append(filtered, arg)
 		}
@@ -57,7 +132,8 @@ var runCmd = &cobra.Command{
 			return
 		}
 
-		args = parseFlags(args)
+		var options cmd.RunOptions
+		args = parseFlags(args, &options)
 		if !haveMinArgs(args) {
 			return
 		}
@@ -65,22 +141,54 @@ var runCmd = &cobra.Command{
 		workflowName := args[0]
 		args = args[1:]
 
-		startKube()
+		if len(options.Events) > 0 {
+			log.SetOutput(os.Stderr)
+		}
 
-		err := cmd.Run(workflowName, args)
+		err := cmd.SelectBackend(&options)
 		if err != nil {
-			if os.IsNotExist(err) {
-				fmt.Printf("No workflow named %v", workflowName)
-				fmt.Println()
-			} else if context.Canceled != err {
-				fmt.Printf(err.Error())
-				fmt.Println()
-			}
+			log.Errorf("%v", err.Error())
+			os.Exit(1)
+		}
+
+		if options.RequiresSandbox() {
+			startKube()
 		}
+
+		runWorkflow(workflowName, args, &options)
 	},
 }
 
+func runWorkflow(workflowName string, args []string, options *cmd.RunOptions) {
+	err := cmd.Run(workflowName, args, options)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Errorf("No workflow named %v", workflowName)
+		} else if context.Canceled != err {
+			log.Errorf("%v", err.Error())
+		}
+
+		os.Exit(1)
+	}
+}
+
 func init() {
 	configureKubeStartingCommandFlags(runCmd)
+	runCmd.Flags().String("from", "", "Resume the last run of the workflow from the specified step, reusing the results of the steps before it")
+	runCmd.Flags().Bool("resume", false, "Resume the last run of the workflow from the first step that did not complete")
+	runCmd.Flags().String("only", "", "Run only the specified steps (separated by commas), along with the services they depend on")
+	runCmd.Flags().String("skip", "", "Skip the specified steps (separated by commas)")
+	runCmd.Flags().Bool("no-services", false, "Don't start any service steps")
+	runCmd.Flags().String("events", "", "Emit a machine-readable stream of events on standard output, in the specified format: jsonl")
+	runCmd.Flags().Bool("dry-run", false, "Print how each step would be built and run, without building or running anything")
+	runCmd.Flags().StringP("output", "o", "", "Output format of --dry-run, one of: yaml, json")
+	runCmd.Flags().StringSlice("report", nil, "Produce a report once the workflow finishes: summary, or junit=<file> (can be specified more than once)")
+	runCmd.Flags().String("backend", "", "Backend to run steps with: kube (the default), or docker to run them as plain containers. Defaults to the contents of .sbox/backend")
+	runCmd.Flags().String("kubeconfig", "", "Run steps on an existing Kubernetes cluster configured in the specified kubeconfig file, instead of the Sandbox VM")
+	runCmd.Flags().String("context", "", "Run steps on the existing Kubernetes cluster of the specified kubeconfig context, instead of the Sandbox VM")
+	runCmd.Flags().String("registry", "", "Registry which step images are pushed to, so that an existing cluster can pull them (required with --kubeconfig or --context)")
+	runCmd.Flags().String("resume-run", "", "Resume a run whose CLI exited before it finished, from the state recorded in its workflow resource. The steps which completed are skipped, the steps which were still running are reattached to while their pods exist, and the rest are run")
+	runCmd.Flags().Bool("timings", false, "Print a breakdown of the time spent in each phase of building and running each step")
+	runCmd.Flags().String("watch", "", "Re-run the workflow whenever files in its steps' build contexts change. Use --watch=changed to restart from the first step whose context changed")
 	RootCmd.AddCommand(runCmd)
 }
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/cmd"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+var testOptions cmd.TestOptions
+
+var testWorkflowCmd = &cobra.Command{
+	Use:   "test-workflow <workflow> [args]",
+	Short: "Test the control flow of a workflow, without building or running any of its steps",
+	Long: `Test the control flow of a workflow, without building or running any of its steps.
+
+Steps are simulated by a fake backend, according to a test script. The script describes
+the output and failures of each step, and the expected outcome of the run:
+
+  steps:
+    build:
+      output: ["var version=1.2"]
+    deploy:
+      failure: Unable to deploy
+  expect:
+    status: failed
+    steps:
+      deploy: failed
+    variables:
+      version: "1.2"
+    events:
+      - type: change
+        step: deploy
+        status: failed
+
+The script is read from <workflow>.test.yml in the workflows directory, unless one is
+specified with --script. Expected events must be emitted in the order they are listed,
+and any fields which are left out match any value.`,
+	Run: func(command *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Println("You must specify a workflow!")
+			fmt.Println()
+			fmt.Println("Try running `sbox test-workflow --help` for help")
+			return
+		}
+
+		err := cmd.Test(args[0], args[1:], &testOptions)
+		if err != nil {
+			if os.IsNotExist(err) {
+				log.Errorf("No workflow named %v", args[0])
+			} else if context.Canceled != err {
+				log.Errorf("%v", err.Error())
+			}
+
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	testWorkflowCmd.Flags().StringVar(&testOptions.Script, "script", "", "Test script which describes how steps behave, and the expected outcome")
+	testWorkflowCmd.Flags().BoolVar(&testOptions.Events, "events", false, "Emit the events of the run on standard output, as lines of JSON")
+	RootCmd.AddCommand(testWorkflowCmd)
+}
@@ -47,6 +47,11 @@ func (b *lineBuffer) Write(src []byte) (int, error) {
 	return srcLength, nil
 }
 
+// Unlimited Limit producer for line buffers which don't limit the length of lines
+func Unlimited() int {
+	return 0
+}
+
 // NewLineBuffer Create line buffer which sends specified receiver of lines and uses the given limit producer
 func NewLineBuffer(lineReceiver func([]byte), limitProducer func() int) io.Writer {
 	if lineReceiver == nil {
@@ -54,7 +59,7 @@ func NewLineBuffer(lineReceiver func([]byte), limitProducer func() int) io.Write
 	}
 
 	if limitProducer == nil {
-		limitProducer = func() int { return -1 }
+		limitProducer = Unlimited
 	}
 
 	buffer := &limitedBuffer{}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/files"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/graph"
+)
+
+// Graph Print a graph of the steps in a workflow in the current project, in DOT or Mermaid format
+func Graph(workflowName string, format string) error {
+	workflow, err := files.ReadWorkflow(workflowName)
+	if err != nil {
+		return err
+	}
+
+	workflowGraph := graph.Build(workflow)
+
+	switch format {
+	case "", "dot":
+		return workflowGraph.WriteDot(os.Stdout)
+	case "mermaid":
+		return workflowGraph.WriteMermaid(os.Stdout)
+	}
+
+	return errors.New(`Unknown graph format "` + format + `", must be one of: dot, mermaid`)
+}
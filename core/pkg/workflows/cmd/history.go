@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/history"
+)
+
+func formatDuration(run *history.Run) string {
+	state := &run.Spec.State
+	if state.Started == nil {
+		return ""
+	}
+
+	finished := time.Now()
+	if state.Finished != nil {
+		finished = state.Finished.Time
+	}
+
+	return (finished.Sub(state.Started.Time) / time.Second * time.Second).String()
+}
+
+func formatStarted(run *history.Run) string {
+	if run.Spec.State.Started == nil {
+		return ""
+	}
+
+	return run.Spec.State.Started.Local().Format("2006-01-02 15:04:05")
+}
+
+// History Print the history of workflow runs in the current project
+func History() error {
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	runs, err := history.List(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	if len(runs) < 1 {
+		fmt.Println("No runs")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tWORKFLOW\tSTATUS\tSTARTED\tDURATION")
+	for _, run := range runs {
+		fmt.Fprintf(writer, "%v\t%v\t%v\t%v\t%v\n",
+			run.ID, run.Workflow, run.Status(), formatStarted(run), formatDuration(run))
+	}
+
+	return writer.Flush()
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/docker/engine-api/client"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/docker"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/files"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/pull"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+	"github.com/stackfoundation/sandbox/progress"
+)
+
+// pinnedTags Get the tags which images pinned to a digest are referenced by in a workflow, by tag, as images
+// saved by digest have no tags once they are loaded
+func pinnedTags(workflow *v1.Workflow, images []string) map[string]string {
+	saved := make(map[string]bool, len(images))
+	for _, image := range images {
+		saved[image] = true
+	}
+
+	tags := make(map[string]string)
+	for tag, pinned := range workflow.Spec.State.Pins {
+		if saved[pinned] {
+			tags[tag] = pinned
+		}
+	}
+
+	return tags
+}
+
+// saveBundle Save images to a tar archive at the specified path. The archive is written to a temporary file
+// first, so that an archive which was only partly written never replaces an existing archive
+func saveBundle(ctx context.Context, dockerClient *client.Client, images []string, tags map[string]string,
+	path string) error {
+	temporaryPath := path + ".tmp"
+
+	output, err := os.Create(temporaryPath)
+	if err != nil {
+		return err
+	}
+
+	err = docker.SaveImages(ctx, dockerClient, images, tags, output)
+	closeErr := output.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(temporaryPath)
+		return err
+	}
+
+	return os.Rename(temporaryPath, path)
+}
+
+// SaveImages Save the images referenced by a workflow in the current project to a tar archive, pulling
+// any images which aren't present yet, so that the workflow can be run on machines which can't pull them
+func SaveImages(workflowName string, path string, options *RunOptions) error {
+	workflow, err := files.ReadWorkflow(workflowName)
+	if err != nil {
+		return err
+	}
+
+	workflow.Spec.State.Pins, err = files.ReadImageLock(workflowName)
+	if err != nil {
+		return err
+	}
+
+	dockerClient, err := coordinator.DockerClient(options.coordinatorOptions())
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	images, err := pull.Fetch(ctx, dockerClient, workflow)
+	if err != nil {
+		return err
+	}
+
+	if len(images) < 1 {
+		return fmt.Errorf("Workflow %v doesn't reference any images", workflowName)
+	}
+
+	log.Infof("Saving images:")
+	for _, image := range images {
+		log.Infof("  %v", image)
+	}
+
+	err = saveBundle(ctx, dockerClient, images, pinnedTags(workflow, images), path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Saved %v images to %v\n", len(images), path)
+	return nil
+}
+
+// LoadImages Load the images in a tar archive which was written by SaveImages, and tag the images which were
+// saved by digest with the tags they are referenced by
+func LoadImages(path string, options *RunOptions) error {
+	archive, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	info, err := archive.Stat()
+	if err != nil {
+		return err
+	}
+
+	tags, err := docker.BundleTags(archive)
+	if err != nil {
+		return err
+	}
+
+	_, err = archive.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	dockerClient, err := coordinator.DockerClient(options.coordinatorOptions())
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	reader := progress.NewProgressAwareReader(archive, "Loading images", "ImageLoad", info.Size())
+
+	err = docker.LoadImages(ctx, dockerClient, reader, log.Output())
+	if err != nil {
+		return err
+	}
+
+	return docker.TagBundleImages(ctx, dockerClient, tags)
+}
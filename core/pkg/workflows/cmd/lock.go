@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/files"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/pull"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+// Lock Resolve the images referenced by a workflow in the current project to the digests they are at now,
+// and write them to the lockfile of the workflow, so that later runs use the same images
+func Lock(workflowName string, options *RunOptions) error {
+	workflow, err := files.ReadWorkflow(workflowName)
+	if err != nil {
+		return err
+	}
+
+	dockerClient, err := coordinator.DockerClient(options.coordinatorOptions())
+	if err != nil {
+		return err
+	}
+
+	pins, err := pull.Lock(context.Background(), dockerClient, workflow)
+	if err != nil {
+		return err
+	}
+
+	images := make([]string, 0, len(pins))
+	for image := range pins {
+		images = append(images, image)
+	}
+
+	sort.Strings(images)
+	for _, image := range images {
+		fmt.Printf("%v -> %v\n", image, pins[image])
+	}
+
+	path, err := files.WriteImageLock(workflowName, pins)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Pinned %v images in %v\n", len(pins), path)
+	return nil
+}
+
+func prePullImages(ctx context.Context, options *coordinator.Options, workflow *v1.Workflow) error {
+	dockerClient, err := coordinator.DockerClient(options)
+	if err != nil {
+		return err
+	}
+
+	return pull.PrePull(ctx, dockerClient, workflow)
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/history"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+const followInterval = 500 * time.Millisecond
+
+type logTail struct {
+	offsets map[string]int64
+	printed map[string]bool
+}
+
+func (t *logTail) printNew(run *history.Run, step *history.StepRecord, header bool) error {
+	file, err := os.Open(run.LogFile(step))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = file.Seek(t.offsets[step.Log], io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	if header && !t.printed[step.Log] {
+		info, err := file.Stat()
+		if err != nil || info.Size() <= t.offsets[step.Log] {
+			return err
+		}
+
+		fmt.Println("==> " + step.Path + " <==")
+		t.printed[step.Log] = true
+	}
+
+	written, err := io.Copy(os.Stdout, file)
+	t.offsets[step.Log] += written
+	return err
+}
+
+func (t *logTail) print(run *history.Run, stepName string) error {
+	if len(stepName) > 0 {
+		step := run.FindStep(stepName)
+		if step == nil {
+			return errors.New(`No step named "` + stepName + `" in run ` + run.ID)
+		}
+
+		return t.printNew(run, step, false)
+	}
+
+	for i := range run.Steps {
+		err := t.printNew(run, &run.Steps[i], true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Logs Print the logs of a recorded workflow run, optionally following them until the run finishes
+func Logs(runReference, stepName string, follow bool) error {
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	run, err := history.Find(projectRoot, runReference)
+	if err != nil {
+		return err
+	}
+
+	tail := &logTail{
+		offsets: make(map[string]int64),
+		printed: make(map[string]bool),
+	}
+
+	for {
+		err = tail.print(run, stepName)
+		if err != nil {
+			return err
+		}
+
+		if !follow || run.Status() != v1.WorkflowRunning {
+			return nil
+		}
+
+		time.Sleep(followInterval)
+
+		run, err = run.Reload()
+		if err != nil {
+			return err
+		}
+
+		if run.Status() != v1.WorkflowRunning {
+			follow = false
+		}
+	}
+}
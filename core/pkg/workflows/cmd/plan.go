@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/plan"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+func printPlan(workflow *v1.Workflow, output string) error {
+	workflowPlan := plan.Plan(workflow)
+
+	switch output {
+	case "":
+		return workflowPlan.Text(os.Stdout)
+	case "yaml":
+		return workflowPlan.YAML(os.Stdout)
+	case "json":
+		return workflowPlan.JSON(os.Stdout)
+	}
+
+	return errors.New(`Unknown output format "` + output + `", must be one of: yaml, json`)
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/go-units"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/files"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/kube"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/prune"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+// PruneOptions Options which control which generated artifacts are pruned
+type PruneOptions struct {
+	All         bool
+	Backend     string
+	DryRun      bool
+	KubeContext string
+	Kubeconfig  string
+	OlderThan   time.Duration
+	Project     string
+}
+
+func (options *PruneOptions) pruneOptions() (*prune.Options, error) {
+	project := options.Project
+	if len(project) == 0 {
+		workingDirectory, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+
+		project = workingDirectory
+	}
+
+	project, err := filepath.Abs(project)
+	if err != nil {
+		return nil, err
+	}
+
+	return &prune.Options{
+		All:       options.All,
+		DryRun:    options.DryRun,
+		OlderThan: options.OlderThan,
+		Project:   project,
+	}, nil
+}
+
+// selectPruneBackend Select the backend whose artifacts are pruned. Unlike running a workflow, pruning an
+// existing cluster doesn't need a registry, as no images are pushed
+func selectPruneBackend(options *PruneOptions) (*coordinator.Options, error) {
+	backend, err := selectBackend(options.Backend)
+	if err != nil {
+		return nil, err
+	}
+
+	coordinatorOptions := &coordinator.Options{
+		Backend:    backend,
+		Context:    options.KubeContext,
+		Kubeconfig: options.Kubeconfig,
+	}
+
+	if backend == coordinator.DockerBackend && coordinatorOptions.Cluster() {
+		return nil, errors.New("--kubeconfig and --context can only be used with the " + coordinator.KubeBackend + " backend")
+	}
+
+	return coordinatorOptions, nil
+}
+
+func kubeClientForPrune(options *coordinator.Options) *kubernetes.Clientset {
+	if options.Backend != coordinator.KubeBackend {
+		return nil
+	}
+
+	var clientSet *kubernetes.Clientset
+	var err error
+	if options.Cluster() {
+		clientSet, err = kube.CreateClusterKubeClient(options.Kubeconfig, options.Context)
+	} else {
+		clientSet, err = kube.CreateKubeClient()
+	}
+
+	if err != nil {
+		log.Debugf("Unable to connect to Kubernetes, services won't be pruned: %v", err.Error())
+		return nil
+	}
+
+	return clientSet
+}
+
+func workflowsClientForPrune(options *coordinator.Options) *rest.RESTClient {
+	if options.Backend != coordinator.KubeBackend {
+		return nil
+	}
+
+	workflowsClient, err := kube.CreateWorkflowsClientFor(options.Kubeconfig, options.Context)
+	if err != nil {
+		log.Debugf("Unable to connect to Kubernetes, workflow resources won't be pruned: %v", err.Error())
+		return nil
+	}
+
+	return workflowsClient
+}
+
+func formatSize(size int64) string {
+	if size <= 0 {
+		return ""
+	}
+
+	return units.HumanSize(float64(size))
+}
+
+func printPruneReport(report *prune.Report, dryRun bool) error {
+	if len(report.Artifacts) < 1 {
+		fmt.Println("Nothing to prune")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "KIND\tNAME\tSIZE\tCREATED")
+	for _, artifact := range report.Artifacts {
+		fmt.Fprintf(writer, "%v\t%v\t%v\t%v\n", artifact.Kind, artifact.Name, formatSize(artifact.Size),
+			artifact.Created.Local().Format("2006-01-02 15:04:05"))
+	}
+
+	err := writer.Flush()
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Printf("Would reclaim up to %v\n", units.HumanSize(float64(report.Reclaimed)))
+	} else {
+		fmt.Printf("Reclaimed %v\n", units.HumanSize(float64(report.Reclaimed)))
+	}
+
+	return nil
+}
+
+// Prune Remove the images, containers, services and workflow resources generated by Sandbox for the current project, or for all
+// projects, and print what was removed
+func Prune(options *PruneOptions) error {
+	coordinatorOptions, err := selectPruneBackend(options)
+	if err != nil {
+		return err
+	}
+
+	pruneOptions, err := options.pruneOptions()
+	if err != nil {
+		return err
+	}
+
+	dockerClient, err := coordinator.DockerClient(coordinatorOptions)
+	if err != nil {
+		return err
+	}
+
+	report, err := prune.Prune(context.Background(), dockerClient, kubeClientForPrune(coordinatorOptions),
+		workflowsClientForPrune(coordinatorOptions), pruneOptions)
+	if err != nil {
+		return err
+	}
+
+	return printPruneReport(report, options.DryRun)
+}
+
+// pruneThreshold Get the disk usage (in bytes) above which the oldest generated images are pruned before step
+// images are built, configured for the project. No threshold (0) is returned if none is configured, or if the
+// configured threshold is invalid, as pruning never stops a workflow from running
+func pruneThreshold() int64 {
+	configured, err := files.PruneThreshold()
+	if err != nil || len(configured) == 0 {
+		return 0
+	}
+
+	threshold, err := units.RAMInBytes(configured)
+	if err != nil {
+		log.Errorf("Invalid prune threshold %v in .sbox/prune-threshold: %v", configured, err.Error())
+		return 0
+	}
+
+	return threshold
+}
@@ -2,13 +2,25 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/signal"
 	"strconv"
 	"strings"
 
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/crd"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/docker"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/events"
+	executioncontext "github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/controller"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/files"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/filter"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/history"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/kube"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/report"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/resume"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/timings"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/validation"
 	"github.com/stackfoundation/sandbox/log"
@@ -26,13 +38,198 @@ func addArgumentVariables(workflow *v1.Workflow, args []string) {
 	}
 }
 
-// Run Run a workflow in the current project
-func Run(workflowName string, args []string) error {
+// RunOptions Options which control how a workflow is run
+type RunOptions struct {
+	Backend     string
+	DryRun      bool
+	Events      string
+	From        string
+	KubeContext string
+	Kubeconfig  string
+	NoServices  bool
+	Only        []string
+	Output      string
+	Registry    string
+	Report      []string
+	Resume      bool
+	ResumeRun   string
+	Skip        []string
+	Timings     bool
+	Watch       string
+}
+
+func (options *RunOptions) coordinatorOptions() *coordinator.Options {
+	return &coordinator.Options{
+		Backend:    options.Backend,
+		Context:    options.KubeContext,
+		Kubeconfig: options.Kubeconfig,
+		Registry:   options.Registry,
+	}
+}
+
+// RequiresSandbox Does running a workflow with these options require the Sandbox VM to be running?
+func (options *RunOptions) RequiresSandbox() bool {
+	return !options.DryRun && options.Backend == coordinator.KubeBackend && !options.coordinatorOptions().Cluster()
+}
+
+// SelectBackend Select the backend steps are run with, falling back to the backend configured for the
+// project, and then to Kubernetes. The options are then checked to be consistent with the backend
+func SelectBackend(options *RunOptions) error {
+	backend, err := selectBackend(options.Backend)
+	if err != nil {
+		return err
+	}
+
+	options.Backend = backend
+	return options.coordinatorOptions().Validate()
+}
+
+// selectBackend Get the specified backend, falling back to the backend configured for the project, and then
+// to Kubernetes
+func selectBackend(backend string) (string, error) {
+	if len(backend) == 0 {
+		projectBackend, err := files.ProjectBackend()
+		if err != nil {
+			return "", err
+		}
+
+		backend = projectBackend
+	}
+
+	if len(backend) == 0 {
+		return coordinator.KubeBackend, nil
+	}
+
+	return backend, nil
+}
+
+func resumeWorkflow(workflow *v1.Workflow, options *RunOptions) error {
+	run, err := history.Latest(workflow.Spec.State.ProjectRoot, workflow.Name)
+	if err != nil {
+		return err
+	}
+
+	dockerClient, err := coordinator.DockerClient(options.coordinatorOptions())
+	if err != nil {
+		return err
+	}
+
+	return resume.Resume(dockerClient, workflow, run, options.From)
+}
+
+// resumeClusterRun Resume a run from the state recorded in its workflow resource. The resumed run keeps the ID of
+// the run, so that its namespace is taken over rather than deleted as an orphan, and the steps which were still
+// running are reattached to, following their pods instead of running them again
+func resumeClusterRun(workflow *v1.Workflow, options *RunOptions) error {
+	run, err := crd.ReadRun(options.Kubeconfig, options.KubeContext, options.ResumeRun)
+	if err != nil {
+		return err
+	}
+
+	if run.Workflow != workflow.Name {
+		return errors.New("Run " + run.ID + " is a run of workflow " + run.Workflow + ", not " + workflow.Name)
+	}
+
+	dockerClient, err := coordinator.DockerClient(options.coordinatorOptions())
+	if err != nil {
+		return err
+	}
+
+	err = resume.Resume(dockerClient, workflow, run, options.From)
+	if err != nil {
+		return err
+	}
+
+	workflow.Spec.State.ID = run.ID
+	resume.Reattach(workflow, run)
+	return nil
+}
+
+func createMirror(options *RunOptions) executioncontext.Observer {
+	if options.Backend != coordinator.KubeBackend {
+		return nil
+	}
+
+	mirror, err := crd.NewMirror(options.Kubeconfig, options.KubeContext)
+	if err != nil {
+		log.Debugf("Unable to mirror the run in a workflow resource: %v", err.Error())
+		return nil
+	}
+
+	return mirror
+}
+
+func createEventEmitter(format string) (executioncontext.Observer, error) {
+	switch format {
+	case "":
+		return nil, nil
+	case "jsonl":
+		log.SetOutput(os.Stderr)
+		return events.NewEmitter(os.Stdout), nil
+	}
+
+	return nil, errors.New(`Unknown event format "` + format + `", must be: jsonl`)
+}
+
+func createReporter(reports []string) (executioncontext.Observer, error) {
+	if len(reports) == 0 {
+		return nil, nil
+	}
+
+	return report.NewReporter(reports)
+}
+
+func workflowResult(workflow *v1.Workflow) error {
+	switch workflow.Spec.State.Status {
+	case v1.WorkflowSucceeded:
+		return nil
+	case v1.WorkflowCancelled:
+		return context.Canceled
+	}
+
+	return errors.New("Workflow " + workflow.Name + " failed")
+}
+
+func runWorkflow(ctx context.Context, workflowName string, args []string, options *RunOptions) error {
+	var observers []executioncontext.Observer
+
+	emitter, err := createEventEmitter(options.Events)
+	if err != nil {
+		return err
+	}
+
+	reporter, err := createReporter(options.Report)
+	if err != nil {
+		return err
+	}
+
 	workflow, err := files.ReadWorkflow(workflowName)
 	if err != nil {
 		return err
 	}
 
+	workflow.Spec.State.Pins, err = files.ReadImageLock(workflowName)
+	if err != nil {
+		return err
+	}
+
+	if len(options.ResumeRun) > 0 {
+		err = resumeClusterRun(workflow, options)
+		if err != nil {
+			return err
+		}
+	} else if options.Resume {
+		err = resumeWorkflow(workflow, options)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = filter.Filter(workflow, options.Only, options.Skip, options.NoServices)
+	if err != nil {
+		return err
+	}
+
 	addArgumentVariables(workflow, args)
 
 	err = validation.Validate(&workflow.Spec)
@@ -40,22 +237,86 @@ func Run(workflowName string, args []string) error {
 		return err
 	}
 
-	c, err := controller.NewController()
+	if options.DryRun {
+		return printPlan(workflow, options.Output)
+	}
+
+	recorder, err := history.NewRecorder(workflow)
+	if err != nil {
+		log.Debugf("Unable to record the run in the run history: %v", err.Error())
+	} else {
+		observers = append(observers, recorder)
+	}
+
+	if emitter != nil {
+		observers = append(observers, emitter)
+	}
+
+	if reporter != nil {
+		observers = append(observers, reporter)
+	}
+
+	mirror := createMirror(options)
+	if mirror != nil {
+		observers = append(observers, mirror)
+	}
+
+	err = prePullImages(ctx, options.coordinatorOptions(), workflow)
+	if err != nil {
+		return err
+	}
+
+	coordinatorOptions := options.coordinatorOptions()
+	coordinatorOptions.PruneThreshold = pruneThreshold()
+
+	c, err := coordinator.NewCoordinator(coordinatorOptions, workflow)
 	if err != nil {
 		return err
 	}
 
-	context, cancel := context.WithCancel(context.Background())
+	controller.NewController(c, observers...).Execute(ctx, workflow)
+	c.Close()
 
+	if options.Timings {
+		timings.Print(log.Output(), workflow)
+	}
+
+	return workflowResult(workflow)
+}
+
+func handleInterrupts(cancel context.CancelFunc) {
 	interruptChannel := make(chan os.Signal, 1)
 	signal.Notify(interruptChannel, os.Interrupt)
 	go func() {
+		interrupted := false
 		for _ = range interruptChannel {
-			log.Debugf("An interrupt was requested, performing clean-up!")
+			if interrupted {
+				log.Infof("Forcing clean-up and exiting!")
+				kube.ForceCleanup()
+				docker.ForceCleanup()
+				os.Exit(1)
+			}
+
+			interrupted = true
+			log.Infof("Stopping workflow and cleaning up, press Ctrl+C again to force it to stop")
 			cancel()
 		}
 	}()
+}
 
-	c.Execute(context, workflow)
-	return nil
+// Run Run a workflow in the current project
+func Run(workflowName string, args []string, options *RunOptions) error {
+	err := SelectBackend(options)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handleInterrupts(cancel)
+
+	if len(options.Watch) > 0 {
+		return watchWorkflow(ctx, workflowName, args, options)
+	}
+
+	return runWorkflow(ctx, workflowName, args, options)
 }
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/events"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/controller"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator/fake"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/files"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/validation"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+const testScriptExtension = ".test.yml"
+
+// TestOptions Options which control how a workflow is tested
+type TestOptions struct {
+	Events bool
+	Script string
+}
+
+func readTestScript(workflowName string, path string) (*fake.Script, error) {
+	if len(path) > 0 {
+		script, err := fake.ReadScript(path)
+		if err != nil {
+			return nil, errors.New("Unable to read test script " + path + ": " + err.Error())
+		}
+
+		return script, nil
+	}
+
+	workflowsDirectory, err := files.WorkflowsDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	script, err := fake.ReadScript(filepath.Join(workflowsDirectory, workflowName+testScriptExtension))
+	if os.IsNotExist(err) {
+		return &fake.Script{}, nil
+	}
+
+	return script, err
+}
+
+func decodeEvents(reader io.Reader) ([]events.Event, error) {
+	var emitted []events.Event
+
+	decoder := json.NewDecoder(reader)
+	for {
+		var event events.Event
+		err := decoder.Decode(&event)
+		if err == io.EOF {
+			return emitted, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		emitted = append(emitted, event)
+	}
+}
+
+// Test Run a workflow in the current project with a fake coordinator, which simulates its steps according to a
+// test script instead of building and running them. The outcome of the run is checked against the expectations
+// in the script. The script defaults to <workflow>.test.yml in the workflows directory
+func Test(workflowName string, args []string, options *TestOptions) error {
+	script, err := readTestScript(workflowName, options.Script)
+	if err != nil {
+		return err
+	}
+
+	workflow, err := files.ReadWorkflow(workflowName)
+	if err != nil {
+		return err
+	}
+
+	addArgumentVariables(workflow, args)
+
+	err = validation.Validate(&workflow.Spec)
+	if err != nil {
+		return err
+	}
+
+	var recorded bytes.Buffer
+	var output io.Writer = &recorded
+	if options.Events {
+		log.SetOutput(os.Stderr)
+		output = io.MultiWriter(&recorded, os.Stdout)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handleInterrupts(cancel)
+
+	controller.NewController(fake.NewCoordinator(script), events.NewEmitter(output)).Execute(ctx, workflow)
+
+	if script.Expect == nil {
+		return workflowResult(workflow)
+	}
+
+	emitted, err := decodeEvents(&recorded)
+	if err != nil {
+		return err
+	}
+
+	err = script.Expect.Check(workflow, emitted)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Workflow %v met all expectations", workflow.Name)
+	return nil
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/files"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/filter"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/report"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/watch"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+const watchAll = "all"
+const watchChanged = "changed"
+
+const watchDebounce = 500 * time.Millisecond
+
+// Period after a run finishes in which changes are assumed to have been made by the run writing its outputs
+const watchSettle = 2 * watchDebounce
+
+func outputPaths(projectRoot string, workflow *v1.Workflow, options *RunOptions) []string {
+	paths := watch.VolumePaths(workflow, projectRoot)
+	for _, file := range report.OutputFiles(options.Report) {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(projectRoot, file)
+		}
+
+		paths = append(paths, file)
+	}
+
+	return paths
+}
+
+func changedStep(workflowName string, changes []string, options *RunOptions) (bool, string, error) {
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return false, "", err
+	}
+
+	workflowsDirectory, err := files.WorkflowsDirectory()
+	if err == nil && watch.IncludesWorkflows(projectRoot, workflowsDirectory, changes) {
+		return true, "", nil
+	}
+
+	workflow, err := files.ReadWorkflow(workflowName)
+	if err != nil {
+		return true, "", nil
+	}
+
+	changes = watch.WithoutOutputs(projectRoot, changes, outputPaths(projectRoot, workflow, options))
+	if len(changes) == 0 {
+		return false, "", nil
+	}
+
+	err = filter.Filter(workflow, options.Only, options.Skip, options.NoServices)
+	if err != nil {
+		return true, "", nil
+	}
+
+	changed, err := watch.ChangedSteps(workflow, changes)
+	if err != nil {
+		return false, "", err
+	}
+
+	if len(changed) == 0 {
+		return false, "", nil
+	}
+
+	if options.Watch == watchChanged {
+		log.Debugf("Steps affected by changes: %v", changed)
+		return true, changed[0], nil
+	}
+
+	return true, "", nil
+}
+
+func reportWatchedRun(workflowName string, err error) {
+	if err != nil && err != context.Canceled {
+		if os.IsNotExist(err) {
+			log.Errorf("No workflow named %v", workflowName)
+		} else {
+			log.Errorf("%v", err.Error())
+		}
+	}
+
+	log.Infof("Watching for changes...")
+}
+
+func watchWorkflow(ctx context.Context, workflowName string, args []string, options *RunOptions) error {
+	if options.Watch != watchAll && options.Watch != watchChanged {
+		return errors.New(`Unknown watch mode "` + options.Watch + `", must be one of: all, changed`)
+	}
+
+	projectRoot, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	watcher, err := watch.NewWatcher(projectRoot, watchDebounce)
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	runOptions := *options
+	for {
+		runContext, cancel := context.WithCancel(ctx)
+		finished := make(chan error, 1)
+		running := true
+
+		go func(options RunOptions) {
+			finished <- runWorkflow(runContext, workflowName, args, &options)
+		}(runOptions)
+
+		var from string
+		var settled time.Time
+
+	waitForChanges:
+		for {
+			select {
+			case err := <-finished:
+				running = false
+				settled = time.Now().Add(watchSettle)
+				reportWatchedRun(workflowName, err)
+			case changes := <-watcher.Changes():
+				if !running && time.Now().Before(settled) {
+					log.Debugf("Ignoring changes made while the run was writing its outputs: %v", changes)
+					continue
+				}
+
+				restart, changed, err := changedStep(workflowName, changes, options)
+				if err != nil {
+					log.Errorf("%v", err.Error())
+				} else if restart {
+					from = changed
+					break waitForChanges
+				}
+			case <-ctx.Done():
+				cancel()
+				if running {
+					<-finished
+				}
+
+				return ctx.Err()
+			}
+		}
+
+		cancel()
+		if running {
+			log.Infof("Changes detected, stopping the current run of workflow %v", workflowName)
+			<-finished
+		}
+
+		runOptions = *options
+		if len(from) > 0 {
+			log.Infof("Changes detected, restarting workflow %v from step %v", workflowName, from)
+			runOptions.Resume = true
+			runOptions.From = from
+		} else {
+			log.Infof("Changes detected, restarting workflow %v", workflowName)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package crd
+
+import (
+	"encoding/json"
+	"io"
+
+	executioncontext "github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/kube"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewMirror Create a mirror which stores workflow resources in the cluster of the specified kubeconfig file
+// and context, or in the Sandbox VM if neither is specified
+func NewMirror(kubeconfigPath string, context string) (*Mirror, error) {
+	extensionsClient, err := kube.CreateExtensionsClientFor(kubeconfigPath, context)
+	if err != nil {
+		return nil, err
+	}
+
+	err = kube.CreateWorkflowResourceDefinitionIfRequired(
+		extensionsClient.ApiextensionsV1beta1().CustomResourceDefinitions())
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := kube.CreateWorkflowsClientFor(kubeconfigPath, context)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Mirror{
+		client:    client,
+		workflows: make(map[*v1.Workflow]*mirroredWorkflow),
+	}, nil
+}
+
+func snapshot(workflow *v1.Workflow) *v1.WorkflowSpec {
+	var spec v1.WorkflowSpec
+
+	content, err := json.Marshal(&workflow.Spec)
+	if err == nil {
+		err = json.Unmarshal(content, &spec)
+	}
+
+	if err != nil {
+		log.Debugf("Unable to take a snapshot of workflow %v: %v", workflow.Name, err.Error())
+		return nil
+	}
+
+	return &spec
+}
+
+func resourceFor(workflow *v1.Workflow, root *v1.Workflow, spec *v1.WorkflowSpec) *v1.Workflow {
+	return &v1.Workflow{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.WorkflowsGroupName + "/" + v1.WorkflowsGroupVersion,
+			Kind:       v1.WorkflowsKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        workflow.Spec.State.ID,
+			Namespace:   Namespace,
+			Labels:      map[string]string{runLabel: root.Spec.State.ID},
+			Annotations: map[string]string{workflowAnnotation: workflow.Name},
+		},
+		Spec: *spec,
+	}
+}
+
+func (m *Mirror) mirrored(workflow *v1.Workflow) *mirroredWorkflow {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.workflows[workflow]
+}
+
+func (mw *mirroredWorkflow) push(spec *v1.WorkflowSpec) {
+	mw.lock.Lock()
+	defer mw.lock.Unlock()
+
+	if mw.closed || spec == nil {
+		return
+	}
+
+	select {
+	case <-mw.pending:
+	default:
+	}
+
+	mw.pending <- spec
+}
+
+func (mw *mirroredWorkflow) close() {
+	mw.lock.Lock()
+	defer mw.lock.Unlock()
+
+	if !mw.closed {
+		mw.closed = true
+		close(mw.pending)
+	}
+}
+
+func (m *Mirror) sync(mw *mirroredWorkflow) {
+	defer close(mw.done)
+
+	for spec := range mw.pending {
+		updated, err := kube.UpdateWorkflow(m.client, mw.resource, func(workflow *v1.Workflow) {
+			workflow.Spec = *spec
+		})
+		if err != nil {
+			log.Debugf(`Unable to update workflow resource "%v": %v`, mw.resource.Name, err.Error())
+			continue
+		}
+
+		mw.resource = updated
+	}
+}
+
+// WorkflowStarted Upload a workflow resource for a workflow which was started
+func (m *Mirror) WorkflowStarted(workflow *v1.Workflow) {
+	m.lock.Lock()
+	if m.root == nil {
+		m.root = workflow
+	}
+	root := m.root
+	m.lock.Unlock()
+
+	spec := snapshot(workflow)
+	if spec == nil {
+		return
+	}
+
+	uploaded, err := kube.UploadWorkflow(m.client, resourceFor(workflow, root, spec))
+	if err != nil {
+		log.Debugf("Unable to upload a workflow resource for workflow %v: %v", workflow.Name, err.Error())
+		return
+	}
+
+	mw := &mirroredWorkflow{
+		done:     make(chan struct{}),
+		pending:  make(chan *v1.WorkflowSpec, 1),
+		resource: uploaded,
+	}
+
+	m.lock.Lock()
+	m.workflows[workflow] = mw
+	m.lock.Unlock()
+
+	go m.sync(mw)
+}
+
+// ChangeRaised Update the workflow resource of a workflow with the state after a change
+func (m *Mirror) ChangeRaised(workflow *v1.Workflow, change *v1.Change) {
+	mw := m.mirrored(workflow)
+	if mw != nil {
+		mw.push(snapshot(workflow))
+	}
+}
+
+// StepOutput The output of steps is not mirrored
+func (m *Mirror) StepOutput(workflow *v1.Workflow, selector []int, stream executioncontext.OutputStream) io.Writer {
+	return nil
+}
+
+// WorkflowFinished Update the workflow resource of a workflow with its final state, waiting for the update
+// to be stored
+func (m *Mirror) WorkflowFinished(workflow *v1.Workflow) {
+	mw := m.mirrored(workflow)
+	if mw == nil {
+		return
+	}
+
+	mw.push(snapshot(workflow))
+	mw.close()
+	<-mw.done
+
+	m.lock.Lock()
+	delete(m.workflows, workflow)
+	m.lock.Unlock()
+}
@@ -0,0 +1,32 @@
+package crd
+
+import (
+	"errors"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/history"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/kube"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ReadRun Read the state of a workflow run from the workflow resource mirroring it, in the cluster of the
+// specified kubeconfig file and context, or in the Sandbox VM if neither is specified
+func ReadRun(kubeconfigPath string, context string, id string) (*history.Run, error) {
+	client, err := kube.CreateWorkflowsClientFor(kubeconfigPath, context)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := kube.GetWorkflow(client, Namespace, id)
+	if kubeerr.IsNotFound(err) {
+		return nil, errors.New("No workflow resource for run " + id + " was found in Kubernetes")
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &history.Run{
+		ID:          resource.Name,
+		Workflow:    resource.Annotations[workflowAnnotation],
+		ProjectRoot: resource.Spec.State.ProjectRoot,
+		Spec:        resource.Spec,
+	}, nil
+}
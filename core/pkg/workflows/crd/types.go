@@ -0,0 +1,31 @@
+package crd
+
+import (
+	"sync"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"k8s.io/client-go/rest"
+)
+
+// Namespace Namespace of the workflow resources which mirror workflow runs
+const Namespace = "default"
+
+const runLabel = "sbox-run"
+const workflowAnnotation = "sbox/workflow"
+
+// Mirror An observer which mirrors the state of each workflow in a run in a Workflow custom resource, so that
+// the progress of the run can be followed with kubectl get wf
+type Mirror struct {
+	client    *rest.RESTClient
+	lock      sync.Mutex
+	root      *v1.Workflow
+	workflows map[*v1.Workflow]*mirroredWorkflow
+}
+
+type mirroredWorkflow struct {
+	closed   bool
+	done     chan struct{}
+	lock     sync.Mutex
+	pending  chan *v1.WorkflowSpec
+	resource *v1.Workflow
+}
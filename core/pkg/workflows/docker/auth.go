@@ -0,0 +1,263 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/engine-api/types"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+	"k8s.io/client-go/util/homedir"
+)
+
+// Server address under which Docker stores the credentials for Docker Hub
+const dockerHubServer = "https://index.docker.io/v1/"
+
+// Username returned by credential helpers when the secret is an identity token rather than a password
+const identityTokenUsername = "<token>"
+
+type dockerConfig struct {
+	Auths       map[string]dockerConfigAuth `json:"auths"`
+	CredHelpers map[string]string           `json:"credHelpers"`
+	CredsStore  string                      `json:"credsStore"`
+}
+
+type dockerConfigAuth struct {
+	Auth          string `json:"auth"`
+	IdentityToken string `json:"identitytoken"`
+}
+
+type helperCredentials struct {
+	Secret    string `json:"Secret"`
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+}
+
+// RegistryHost Get the host of the registry the specified image reference is named after
+func RegistryHost(reference string) string {
+	slash := strings.Index(reference, "/")
+	if slash < 0 {
+		return dockerHubServer
+	}
+
+	host := reference[:slash]
+	if strings.ContainsAny(host, ".:") || host == "localhost" {
+		return host
+	}
+
+	return dockerHubServer
+}
+
+func dockerConfigPath() string {
+	configDir := os.Getenv("DOCKER_CONFIG")
+	if len(configDir) < 1 {
+		configDir = filepath.Join(homedir.HomeDir(), ".docker")
+	}
+
+	return filepath.Join(configDir, "config.json")
+}
+
+func readDockerConfig() (*dockerConfig, error) {
+	content, err := ioutil.ReadFile(dockerConfigPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &dockerConfig{}, nil
+		}
+
+		return nil, err
+	}
+
+	var config dockerConfig
+	err = json.Unmarshal(content, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+func serverHost(server string) string {
+	if server == dockerHubServer || server == "docker.io" || server == "index.docker.io" {
+		return "index.docker.io"
+	}
+
+	server = strings.TrimPrefix(server, "https://")
+	server = strings.TrimPrefix(server, "http://")
+
+	slash := strings.Index(server, "/")
+	if slash >= 0 {
+		server = server[:slash]
+	}
+
+	return server
+}
+
+func sameServer(server string, host string) bool {
+	return server == host || serverHost(server) == serverHost(host)
+}
+
+func workflowCredentials(host string, registries []v1.RegistryCredentials) (types.AuthConfig, bool) {
+	for _, registry := range registries {
+		if sameServer(registry.Server, host) {
+			return types.AuthConfig{
+				Username:      registry.Username,
+				Password:      registry.Password,
+				ServerAddress: host,
+			}, true
+		}
+	}
+
+	return types.AuthConfig{}, false
+}
+
+func storedCredentials(host string, auths map[string]dockerConfigAuth) (types.AuthConfig, bool, error) {
+	for server, auth := range auths {
+		if !sameServer(server, host) {
+			continue
+		}
+
+		if len(auth.IdentityToken) > 0 {
+			return types.AuthConfig{IdentityToken: auth.IdentityToken, ServerAddress: host}, true, nil
+		}
+
+		if len(auth.Auth) < 1 {
+			continue
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return types.AuthConfig{}, false, err
+		}
+
+		credentials := strings.SplitN(string(decoded), ":", 2)
+		if len(credentials) == 2 {
+			return types.AuthConfig{
+				Username:      credentials[0],
+				Password:      credentials[1],
+				ServerAddress: host,
+			}, true, nil
+		}
+	}
+
+	return types.AuthConfig{}, false, nil
+}
+
+func helperFor(host string, config *dockerConfig) string {
+	for server, helper := range config.CredHelpers {
+		if sameServer(server, host) {
+			return helper
+		}
+	}
+
+	return config.CredsStore
+}
+
+func helperCredentialsFor(helper string, host string) (types.AuthConfig, bool, error) {
+	var output bytes.Buffer
+	var errorOutput bytes.Buffer
+
+	command := exec.Command("docker-credential-"+helper, "get")
+	command.Stdin = strings.NewReader(host)
+	command.Stdout = &output
+	command.Stderr = &errorOutput
+
+	err := command.Run()
+	if err != nil {
+		message := strings.TrimSpace(output.String() + errorOutput.String())
+		if !strings.Contains(message, "credentials not found") {
+			log.Debugf("Credential helper %v failed for %v: %v %v", helper, host, err.Error(), message)
+		}
+
+		return types.AuthConfig{}, false, nil
+	}
+
+	var credentials helperCredentials
+	err = json.Unmarshal(output.Bytes(), &credentials)
+	if err != nil {
+		return types.AuthConfig{}, false, err
+	}
+
+	if credentials.Username == identityTokenUsername {
+		return types.AuthConfig{IdentityToken: credentials.Secret, ServerAddress: host}, true, nil
+	}
+
+	return types.AuthConfig{
+		Username:      credentials.Username,
+		Password:      credentials.Secret,
+		ServerAddress: host,
+	}, true, nil
+}
+
+// AuthConfig Get the credentials for the specified registry host. Credentials of the workflow take
+// precedence over the ones in the Docker config of the user, which are looked up in its credential helpers
+// first, and then in the credentials stored in the config itself
+func AuthConfig(host string, registries []v1.RegistryCredentials) (types.AuthConfig, bool, error) {
+	authConfig, found := workflowCredentials(host, registries)
+	if found {
+		return authConfig, true, nil
+	}
+
+	config, err := readDockerConfig()
+	if err != nil {
+		return types.AuthConfig{}, false, err
+	}
+
+	helper := helperFor(host, config)
+	if len(helper) > 0 {
+		authConfig, found, err = helperCredentialsFor(helper, host)
+		if err != nil || found {
+			return authConfig, found, err
+		}
+	}
+
+	return storedCredentials(host, config.Auths)
+}
+
+// AuthConfigs Get the credentials for the specified registry hosts, keyed by host. Hosts without any
+// credentials are left out
+func AuthConfigs(hosts []string, registries []v1.RegistryCredentials) (map[string]types.AuthConfig, error) {
+	authConfigs := make(map[string]types.AuthConfig)
+
+	for _, host := range hosts {
+		if _, ok := authConfigs[host]; ok {
+			continue
+		}
+
+		authConfig, found, err := AuthConfig(host, registries)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			authConfigs[host] = authConfig
+		}
+	}
+
+	return authConfigs, nil
+}
+
+// RegistryAuth Get the encoded credentials to send to the registry the specified image reference is named
+// after, or the credentials for anonymous access if there are none
+func RegistryAuth(reference string, registries []v1.RegistryCredentials) (string, error) {
+	authConfig, found, err := AuthConfig(RegistryHost(reference), registries)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return anonymousRegistryAuth, nil
+	}
+
+	content, err := json.Marshal(authConfig)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.URLEncoding.EncodeToString(content), nil
+}
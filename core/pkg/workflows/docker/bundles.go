@@ -0,0 +1,167 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/engine-api/client"
+)
+
+// bundleTagsFile Name of the file in a bundle which lists the tags to give the images in the bundle once they
+// are loaded, by the IDs of the images. Images which are saved by digest have no tags in the archive written by
+// Docker, so they would only be known by their IDs once loaded otherwise
+const bundleTagsFile = "sbox-tags.json"
+
+func copyArchive(archive io.Reader, writer *tar.Writer) error {
+	reader := tar.NewReader(archive)
+
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = writer.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(writer, reader)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func bundleTags(ctx context.Context, dockerClient *client.Client, tags map[string]string) (map[string]string, error) {
+	imageTags := make(map[string]string, len(tags))
+
+	for tag, image := range tags {
+		id, err := ImageID(ctx, dockerClient, image)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(id) > 0 {
+			imageTags[tag] = id
+		}
+	}
+
+	return imageTags, nil
+}
+
+// SaveImages Save the specified images, along with all their layers, to a tar archive written to the output.
+// The images which the specified tags are given to are stored in the archive by their IDs, so that the images
+// can be tagged when they are loaded by LoadImages
+func SaveImages(ctx context.Context, dockerClient *client.Client, images []string, tags map[string]string,
+	output io.Writer) error {
+	imageTags, err := bundleTags(ctx, dockerClient, tags)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(imageTags)
+	if err != nil {
+		return err
+	}
+
+	archive, err := dockerClient.ImageSave(ctx, images)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	writer := tar.NewWriter(output)
+
+	err = copyArchive(archive, writer)
+	if err != nil {
+		return err
+	}
+
+	err = writer.WriteHeader(&tar.Header{
+		Name:    bundleTagsFile,
+		Mode:    0644,
+		ModTime: time.Now(),
+		Size:    int64(len(content)),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(content)
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// BundleTags Read the tags to give the images in a tar archive written by SaveImages, by the IDs of the images.
+// No tags are returned for archives which don't list any
+func BundleTags(archive io.Reader) (map[string]string, error) {
+	reader := tar.NewReader(archive)
+
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Name == bundleTagsFile {
+			var tags map[string]string
+			err = json.NewDecoder(reader).Decode(&tags)
+			return tags, err
+		}
+	}
+}
+
+// TagBundleImages Tag the images loaded from a tar archive with the tags read from it by BundleTags
+func TagBundleImages(ctx context.Context, dockerClient *client.Client, tags map[string]string) error {
+	names := make([]string, 0, len(tags))
+	for tag := range tags {
+		names = append(names, tag)
+	}
+
+	sort.Strings(names)
+
+	for _, tag := range names {
+		err := TagImage(ctx, dockerClient, tags[tag], tag)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadImages Load the images in a tar archive which was written by SaveImages, writing the names of the
+// loaded images to the output on a new line, after any progress shown while the archive was read
+func LoadImages(ctx context.Context, dockerClient *client.Client, archive io.Reader, output io.Writer) error {
+	response, err := dockerClient.ImageLoad(ctx, archive, true)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	fmt.Fprintln(output)
+	if response.JSON {
+		return jsonmessage.DisplayJSONMessagesStream(response.Body, output, 0, false, nil)
+	}
+
+	_, err = io.Copy(output, response.Body)
+	return err
+}
@@ -0,0 +1,84 @@
+package docker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/docker/engine-api/client"
+	"github.com/docker/engine-api/types"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+const defaultStopTimeout = 10
+
+var cleanupLock sync.Mutex
+var pendingCleanups = make(map[*containerContext]bool)
+
+func logCleanup(cc *containerContext, message string, arguments ...interface{}) {
+	if atomic.LoadInt32(&cc.containerFinished) == 0 {
+		log.Infof(message, arguments...)
+	} else {
+		log.Debugf(message, arguments...)
+	}
+}
+
+func stopContainer(cc *containerContext, timeout int) {
+	err := cc.dockerClient.ContainerStop(context.Background(), cc.containerID, timeout)
+	if err != nil && !client.IsErrContainerNotFound(err) {
+		log.Errorf("Error stopping container %v: %v", cc.name, err.Error())
+	}
+}
+
+func removeContainer(cc *containerContext) {
+	err := cc.dockerClient.ContainerRemove(context.Background(), cc.containerID, types.ContainerRemoveOptions{
+		Force:         true,
+		RemoveVolumes: true,
+	})
+	if err != nil && !client.IsErrContainerNotFound(err) {
+		log.Errorf("Error removing container %v: %v", cc.name, err.Error())
+	}
+}
+
+func cleanupContainerIfNecessary(cc *containerContext) {
+	defer cc.creationSpec.Cleanup.Done()
+
+	if cc.joinedNetwork {
+		defer cc.creationSpec.Network.leave()
+	}
+
+	if len(cc.containerID) == 0 {
+		return
+	}
+
+	cleanupLock.Lock()
+	pendingCleanups[cc] = true
+	cleanupLock.Unlock()
+
+	defer func() {
+		cleanupLock.Lock()
+		delete(pendingCleanups, cc)
+		cleanupLock.Unlock()
+	}()
+
+	timeout := parseInt(cc.creationSpec.Grace, defaultStopTimeout)
+
+	logCleanup(cc, "Stopping container %v for step %v", cc.name, cc.creationSpec.LogPrefix)
+	if len(cc.creationSpec.Grace) > 0 && timeout > 0 && atomic.LoadInt32(&cc.containerFinished) == 0 {
+		log.Infof("Waiting up to %v seconds for container %v to stop", timeout, cc.name)
+	}
+
+	stopContainer(cc, timeout)
+	removeContainer(cc)
+}
+
+// ForceCleanup Immediately remove any containers which are still being cleaned up
+func ForceCleanup() {
+	cleanupLock.Lock()
+	defer cleanupLock.Unlock()
+
+	for cc := range pendingCleanups {
+		log.Infof("Force removing container %v", cc.name)
+		removeContainer(cc)
+	}
+}
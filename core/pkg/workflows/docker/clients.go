@@ -63,3 +63,8 @@ func CreateDockerClient() (*client.Client, error) {
 	host := hostDockerEnv["DOCKER_HOST"]
 	return client.NewClient(host, constants.DockerAPIVersion, httpClient, nil)
 }
+
+// CreateEnvDockerClient Create a new docker client pointing to the Docker daemon configured in the environment
+func CreateEnvDockerClient() (*client.Client, error) {
+	return client.NewEnvClient()
+}
@@ -0,0 +1,245 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/engine-api/client"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/container"
+	"github.com/docker/engine-api/types/network"
+	"github.com/docker/go-connections/nat"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/processors"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/properties"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+// CreateAndRunContainer Create and run a container according to the given specifications
+func CreateAndRunContainer(dockerClient *client.Client, creationSpec *ContainerCreationSpec) error {
+	cc := &containerContext{
+		creationSpec: creationSpec,
+		dockerClient: dockerClient,
+		name:         v1.GenerateContainerName(),
+		finished:     make(chan struct{}),
+		logsPrinted:  make(chan struct{}),
+	}
+
+	creationSpec.Cleanup.Add(1)
+	go func() {
+		<-creationSpec.Context.Done()
+		cleanupContainerIfNecessary(cc)
+	}()
+
+	err := createContainer(cc)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Created container %v", cc.name)
+	listener := creationSpec.Listener
+	if listener != nil {
+		listener.Created()
+		listener.Container(cc.containerID)
+	}
+
+	err = dockerClient.ContainerStart(context.Background(), cc.containerID)
+	if err != nil {
+		return err
+	}
+
+	if listener != nil {
+		listener.Started()
+	}
+
+	go printContainerLogs(cc)
+	go waitUntilReady(cc)
+	go monitorHealth(cc)
+	go waitForContainer(cc)
+	return nil
+}
+
+func createEnvironment(environment *properties.Properties) []string {
+	if environment != nil {
+		props := environment.Map()
+		if len(props) > 0 {
+			variables := make([]string, 0, len(props))
+			for k, v := range props {
+				variables = append(variables, k+"="+v)
+			}
+
+			return variables
+		}
+	}
+
+	return nil
+}
+
+func createVolumes(volumes []v1.Volume) ([]string, map[string]struct{}) {
+	var binds []string
+	var containerVolumes map[string]struct{}
+
+	for _, volume := range volumes {
+		if len(volume.HostPath) > 0 {
+			log.Debugf("Mounting host path \"%v\" at \"%v\"", volume.HostPath, volume.MountPath)
+			binds = append(binds, volume.HostPath+":"+volume.MountPath)
+		} else if len(volume.Name) > 0 {
+			log.Debugf("Mounting volume \"%v\" at \"%v\"", volume.Name, volume.MountPath)
+			if containerVolumes == nil {
+				containerVolumes = make(map[string]struct{}, len(volumes))
+			}
+
+			containerVolumes[volume.MountPath] = struct{}{}
+		} else {
+			log.Debugf("No name was specified for non-host volume, ignoring")
+		}
+	}
+
+	return binds, containerVolumes
+}
+
+func createPorts(ports []v1.Port) (nat.PortSet, nat.PortMap) {
+	if len(ports) == 0 {
+		return nil, nil
+	}
+
+	exposedPorts := make(nat.PortSet, len(ports))
+	portBindings := make(nat.PortMap, len(ports))
+
+	for _, port := range ports {
+		protocol := "tcp"
+		if strings.ToLower(port.Protocol) == "udp" {
+			protocol = "udp"
+		}
+
+		containerPort, err := nat.NewPort(protocol, port.Container)
+		if err != nil {
+			log.Debugf("Ignoring invalid container port \"%v\"", port.Container)
+			continue
+		}
+
+		if len(port.Internal) > 0 && port.Internal != port.Container {
+			log.Debugf("Port %v is reachable on container port %v, internal ports are not remapped by the Docker backend",
+				port.Name, port.Container)
+		}
+
+		exposedPorts[containerPort] = struct{}{}
+		if len(port.External) > 0 {
+			portBindings[containerPort] = []nat.PortBinding{{HostPort: port.External}}
+		}
+	}
+
+	return exposedPorts, portBindings
+}
+
+func serviceAliases(ports []v1.Port) []string {
+	var aliases []string
+	for _, port := range ports {
+		if len(port.Name) > 0 {
+			aliases = append(aliases, port.Name)
+		}
+	}
+
+	return aliases
+}
+
+func createContainer(cc *containerContext) error {
+	creationSpec := cc.creationSpec
+
+	binds, volumes := createVolumes(creationSpec.Volumes)
+	exposedPorts, portBindings := createPorts(creationSpec.Ports)
+
+	config := &container.Config{
+		Image:        creationSpec.Image,
+		Cmd:          creationSpec.Command,
+		Env:          createEnvironment(creationSpec.Environment),
+		ExposedPorts: exposedPorts,
+		Labels:       creationSpec.Labels,
+		Volumes:      volumes,
+	}
+
+	hostConfig := &container.HostConfig{
+		Binds:        binds,
+		PortBindings: portBindings,
+	}
+
+	var networkingConfig *network.NetworkingConfig
+	if creationSpec.Network != nil {
+		networkName, err := creationSpec.Network.join()
+		if err != nil {
+			return err
+		}
+
+		cc.joinedNetwork = true
+		hostConfig.NetworkMode = container.NetworkMode(networkName)
+		networkingConfig = &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				networkName: {Aliases: serviceAliases(creationSpec.Ports)},
+			},
+		}
+	}
+
+	response, err := cc.dockerClient.ContainerCreate(context.Background(), config, hostConfig, networkingConfig, cc.name)
+	if err != nil {
+		return err
+	}
+
+	cc.containerID = response.ID
+	return nil
+}
+
+func printContainerLogs(cc *containerContext) {
+	defer close(cc.logsPrinted)
+
+	logs, err := cc.dockerClient.ContainerLogs(context.Background(), cc.containerID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	})
+	if err != nil {
+		log.Errorf("Error reading output of container %v: %v", cc.name, err.Error())
+		return
+	}
+	defer logs.Close()
+
+	reader, writer := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(writer, writer, logs)
+		writer.CloseWithError(err)
+	}()
+
+	creationSpec := cc.creationSpec
+	stream := processors.ProcessOutput(reader, &processors.OutputOptions{
+		LogPrefix:        creationSpec.LogPrefix,
+		Output:           creationSpec.Output,
+		VariableReceiver: creationSpec.VariableReceiver,
+		WorkflowReceiver: creationSpec.WorkflowReceiver,
+	})
+	defer stream.Close()
+
+	_, _ = io.Copy(log.Output(), stream)
+}
+
+func waitForContainer(cc *containerContext) {
+	exitCode, err := cc.dockerClient.ContainerWait(context.Background(), cc.containerID)
+	atomic.StoreInt32(&cc.containerFinished, 1)
+	close(cc.finished)
+	<-cc.logsPrinted
+
+	listener := cc.creationSpec.Listener
+	if listener == nil || cc.creationSpec.Context.Err() != nil {
+		return
+	}
+
+	if err != nil {
+		listener.Done(true, err.Error())
+		return
+	}
+
+	listener.Done(exitCode != 0, fmt.Sprintf("Container exited with code %v", exitCode))
+}
@@ -0,0 +1,51 @@
+package docker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stackfoundation/sandbox/core/pkg/minikube/cluster"
+	"github.com/stackfoundation/sandbox/core/pkg/minikube/machine"
+)
+
+// dataDiskUsageCommand Command which prints the usage (in kilobytes) of the disk which the Docker daemon in the
+// Sandbox VM stores images on
+const dataDiskUsageCommand = "df -Pk /var/lib/docker | tail -n 1"
+
+// parseDiskUsage Get the space used (in bytes) from a line of the POSIX output of df
+func parseDiskUsage(output string) (int64, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 3 {
+		return 0, fmt.Errorf("Unable to read disk usage from \"%v\"", strings.TrimSpace(output))
+	}
+
+	used, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to read disk usage from \"%v\": %v", strings.TrimSpace(output), err.Error())
+	}
+
+	return used * 1024, nil
+}
+
+// VMDiskUsage Get the space used (in bytes) on the disk which the Docker daemon in the Sandbox VM stores
+// images on
+func VMDiskUsage() (int64, error) {
+	machineClient, err := machine.NewAPIClient()
+	if err != nil {
+		return 0, err
+	}
+	defer machineClient.Close()
+
+	host, err := cluster.CheckIfApiExistsAndLoad(machineClient)
+	if err != nil {
+		return 0, err
+	}
+
+	output, err := cluster.RunCommand(host, dataDiskUsageCommand, false)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseDiskUsage(output)
+}
@@ -0,0 +1,310 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/engine-api/types"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+const execPollInterval = 250 * time.Millisecond
+
+// probe A probe which checks whether a container is healthy
+type probe func(ctx context.Context, cc *containerContext) bool
+
+// missingProbeTools Exit code of probe scripts when the container has none of the tools needed to probe it
+const missingProbeTools = 127
+
+type healthCheck struct {
+	grace    time.Duration
+	interval time.Duration
+	probe    probe
+	retries  int
+	timeout  time.Duration
+}
+
+func parseSeconds(value string, defaultValue int) time.Duration {
+	return time.Duration(parseInt(value, defaultValue)) * time.Second
+}
+
+func parseInt(value string, defaultValue int) int {
+	if len(value) > 0 {
+		parsed, err := strconv.Atoi(value)
+		if err == nil {
+			return parsed
+		}
+	}
+
+	return defaultValue
+}
+
+func newHealthCheck(probe probe, options *v1.HealthCheckOptions) *healthCheck {
+	return &healthCheck{
+		grace:    parseSeconds(options.Grace, v1.DefaultGrace),
+		interval: parseSeconds(options.Interval, v1.DefaultInterval),
+		probe:    probe,
+		retries:  parseInt(options.Retries, v1.DefaultRetries),
+		timeout:  parseSeconds(options.Timeout, v1.DefaultTimeout),
+	}
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
+
+func timeoutSeconds(timeout time.Duration) string {
+	seconds := int(timeout / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	return strconv.Itoa(seconds)
+}
+
+// probeScript Create a shell script which runs the commands of the tools in the container until one passes,
+// or exits with missingProbeTools if none of the tools are in the container
+func probeScript(tools [][2]string) string {
+	var script bytes.Buffer
+
+	script.WriteString("found=0\n")
+	for _, tool := range tools {
+		fmt.Fprintf(&script, "if command -v %v >/dev/null 2>&1; then found=1; %v && exit 0; fi\n", tool[0], tool[1])
+	}
+
+	fmt.Fprintf(&script, "[ \"$found\" = 1 ] && exit 1\nexit %v\n", missingProbeTools)
+	return script.String()
+}
+
+// tcpProbeScript Create a script which checks that a port accepts connections from within a container, with
+// nc, or with bash when nc isn't in the container
+func tcpProbeScript(port string, timeout time.Duration) string {
+	return probeScript([][2]string{
+		{"nc", "nc -z -w " + timeoutSeconds(timeout) + " 127.0.0.1 " + shellQuote(port)},
+		{"bash", "bash -c " + shellQuote("exec 3<>/dev/tcp/127.0.0.1/"+port)},
+	})
+}
+
+// httpProbeScript Create a script which requests a path from within a container, with curl, or with wget when
+// curl isn't in the container. Like the probes of the kubelet, certificates aren't verified, and responses
+// with status codes from 200 to 399 count as success, except that wget follows redirects
+func httpProbeScript(check *v1.HTTPHealthCheckOptions, https bool, timeout time.Duration) string {
+	scheme := "http"
+	if https {
+		scheme = "https"
+	}
+
+	url := shellQuote(scheme + "://127.0.0.1:" + check.Port + "/" + strings.TrimPrefix(check.Path, "/"))
+	seconds := timeoutSeconds(timeout)
+
+	curl := "curl -s -f -k -o /dev/null -m " + seconds
+	wget := "wget -q -O /dev/null -T " + seconds
+	if https {
+		wget += " --no-check-certificate"
+	}
+
+	for _, header := range check.Headers {
+		value := shellQuote(header.Name + ": " + header.Value)
+		curl += " -H " + value
+		wget += " --header " + value
+	}
+
+	return probeScript([][2]string{
+		{"curl", curl + " " + url},
+		{"wget", wget + " " + url},
+	})
+}
+
+// execProbe Create a probe which runs a script within the container, so that the container is probed the
+// same way wherever the Docker daemon is, and whatever network the container is on
+func execProbe(script string) probe {
+	command := []string{"/bin/sh", "-c", script}
+	reported := false
+
+	return func(ctx context.Context, cc *containerContext) bool {
+		exitCode, err := execExitCode(ctx, cc, command)
+		if err != nil {
+			log.Debugf("Error running health check in container %v: %v", cc.name, err.Error())
+			return false
+		}
+
+		if exitCode == missingProbeTools && !reported {
+			log.Errorf("Container %v of step %v has none of the tools needed to probe it: nc or bash for TCP "+
+				"checks, and curl or wget for HTTP checks. Use a script check instead",
+				cc.name, cc.creationSpec.LogPrefix)
+			reported = true
+		}
+
+		return exitCode == 0
+	}
+}
+
+func createTCPCheck(check *v1.TCPHealthCheckOptions) *healthCheck {
+	options := &check.HealthCheckOptions
+	timeout := parseSeconds(options.Timeout, v1.DefaultTimeout)
+
+	return newHealthCheck(execProbe(tcpProbeScript(check.Port, timeout)), options)
+}
+
+func createHTTPCheck(check *v1.HTTPHealthCheckOptions, https bool) *healthCheck {
+	options := &check.HealthCheckOptions
+	timeout := parseSeconds(options.Timeout, v1.DefaultTimeout)
+
+	return newHealthCheck(execProbe(httpProbeScript(check, https, timeout)), options)
+}
+
+func createScriptCheck(check *v1.ScriptHealthCheckOptions) *healthCheck {
+	command := []string{"/bin/sh", check.Path}
+
+	return newHealthCheck(func(ctx context.Context, cc *containerContext) bool {
+		exitCode, err := execExitCode(ctx, cc, command)
+		if err != nil {
+			log.Debugf("Error running health check in container %v: %v", cc.name, err.Error())
+			return false
+		}
+
+		return exitCode == 0
+	}, &check.HealthCheckOptions)
+}
+
+func createCheck(check *v1.HealthCheck) *healthCheck {
+	if check != nil {
+		if check.TCP != nil {
+			return createTCPCheck(check.TCP)
+		} else if check.HTTPS != nil {
+			return createHTTPCheck(check.HTTPS, true)
+		} else if check.HTTP != nil {
+			return createHTTPCheck(check.HTTP, false)
+		} else if check.Script != nil {
+			return createScriptCheck(check.Script)
+		}
+	}
+
+	return nil
+}
+
+// execExitCode Run a command in a container, and get its exit code once it finishes
+func execExitCode(ctx context.Context, cc *containerContext, command []string) (int, error) {
+	exec, err := cc.dockerClient.ContainerExecCreate(ctx, cc.containerID, types.ExecConfig{
+		Cmd:    command,
+		Detach: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	err = cc.dockerClient.ContainerExecStart(ctx, exec.ID, types.ExecStartCheck{Detach: true})
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		inspection, err := cc.dockerClient.ContainerExecInspect(ctx, exec.ID)
+		if err != nil {
+			return 0, err
+		}
+
+		if !inspection.Running {
+			return inspection.ExitCode, nil
+		}
+
+		if !sleep(ctx, cc, execPollInterval) {
+			return 0, ctx.Err()
+		}
+	}
+}
+
+func (check *healthCheck) passes(cc *containerContext) bool {
+	ctx, cancel := context.WithTimeout(cc.creationSpec.Context, check.timeout)
+	defer cancel()
+
+	return check.probe(ctx, cc)
+}
+
+func sleep(ctx context.Context, cc *containerContext, duration time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-cc.finished:
+		return false
+	case <-time.After(duration):
+		return true
+	}
+}
+
+func waitUntilReady(cc *containerContext) {
+	listener := cc.creationSpec.Listener
+	if listener == nil {
+		return
+	}
+
+	check := createCheck(cc.creationSpec.Readiness)
+	if check == nil {
+		listener.Ready()
+		return
+	}
+
+	ctx := cc.creationSpec.Context
+	if !sleep(ctx, cc, check.grace) {
+		return
+	}
+
+	failures := 0
+	for !check.passes(cc) {
+		failures++
+		if failures >= check.retries {
+			stopUnhealthyContainer(cc, "Readiness check", failures)
+			return
+		}
+
+		if !sleep(ctx, cc, check.interval) {
+			return
+		}
+	}
+
+	listener.Ready()
+}
+
+func stopUnhealthyContainer(cc *containerContext, check string, failures int) {
+	log.Infof("%v for step %v failed %v times, stopping container %v",
+		check, cc.creationSpec.LogPrefix, failures, cc.name)
+
+	err := cc.dockerClient.ContainerKill(context.Background(), cc.containerID, "KILL")
+	if err != nil {
+		log.Errorf("Error stopping container %v: %v", cc.name, err.Error())
+	}
+}
+
+func monitorHealth(cc *containerContext) {
+	check := createCheck(cc.creationSpec.Health)
+	if check == nil {
+		return
+	}
+
+	ctx := cc.creationSpec.Context
+	if !sleep(ctx, cc, check.grace) {
+		return
+	}
+
+	failures := 0
+	for {
+		if check.passes(cc) {
+			failures = 0
+		} else {
+			failures++
+			if failures >= check.retries {
+				stopUnhealthyContainer(cc, "Health check", failures)
+				return
+			}
+		}
+
+		if !sleep(ctx, cc, check.interval) {
+			return
+		}
+	}
+}
@@ -2,16 +2,21 @@ package docker
 
 import (
 	"context"
+	"errors"
 	"io"
-	"os"
 
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/engine-api/client"
 	"github.com/docker/engine-api/types"
 
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/image"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
 )
 
+// Base64 encoding of an empty JSON object, sent as the credentials for registries which don't need any
+const anonymousRegistryAuth = "e30="
+
 // CommitContainer Commit a container as an image
 func CommitContainer(ctx context.Context, dockerClient *client.Client, containerName string, reference string) error {
 	_, err := dockerClient.ContainerCommit(ctx, containerName, types.ContainerCommitOptions{Reference: reference})
@@ -22,6 +27,48 @@ func CommitContainer(ctx context.Context, dockerClient *client.Client, container
 	return nil
 }
 
+// ImageExists Does the specified image exist?
+func ImageExists(ctx context.Context, dockerClient *client.Client, image string) (bool, error) {
+	_, _, err := dockerClient.ImageInspectWithRaw(ctx, image, false)
+	if err != nil {
+		if client.IsErrImageNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ImageID Get the ID of the specified image, or an empty ID if the image doesn't exist
+func ImageID(ctx context.Context, dockerClient *client.Client, image string) (string, error) {
+	inspect, _, err := dockerClient.ImageInspectWithRaw(ctx, image, false)
+	if err != nil {
+		if client.IsErrImageNotFound(err) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return inspect.ID, nil
+}
+
+// ContainerExists Does the specified container exist?
+func ContainerExists(ctx context.Context, dockerClient *client.Client, containerID string) (bool, error) {
+	_, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		if client.IsErrContainerNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
 // BuildImage Build an image with the specified name & options
 func BuildImage(ctx context.Context, dockerClient *client.Client, imageName string, options *image.BuildOptions) error {
 	var imageStream io.ReadCloser
@@ -35,8 +82,13 @@ func BuildImage(ctx context.Context, dockerClient *client.Client, imageName stri
 	defer imageStream.Close()
 
 	buildOptions := types.ImageBuildOptions{
-		Dockerfile: dockerfileTarEntry,
-		Tags:       []string{imageName},
+		AuthConfigs: options.AuthConfigs,
+		BuildArgs:   options.BuildArgs,
+		Dockerfile:  dockerfileTarEntry,
+		Labels:      options.Labels,
+		NoCache:     options.NoCache,
+		PullParent:  options.Pull,
+		Tags:        []string{imageName},
 	}
 
 	response, err := dockerClient.ImageBuild(ctx, imageStream, buildOptions)
@@ -44,12 +96,17 @@ func BuildImage(ctx context.Context, dockerClient *client.Client, imageName stri
 		return err
 	}
 
-	err = jsonmessage.DisplayJSONMessagesStream(response.Body, os.Stdout, 0, true, nil)
+	var body io.Reader = response.Body
+	if options.Output != nil {
+		body = io.TeeReader(response.Body, options.Output)
+	}
+
+	err = jsonmessage.DisplayJSONMessagesStream(body, log.Output(), 0, true, nil)
 	if err != nil {
 		return err
 	}
 
-	_, err = io.Copy(os.Stdout, response.Body)
+	_, err = io.Copy(log.Output(), body)
 	if err != nil {
 		return err
 	}
@@ -57,28 +114,58 @@ func BuildImage(ctx context.Context, dockerClient *client.Client, imageName stri
 	return nil
 }
 
-func pullImage(ctx context.Context, dockerClient *client.Client, image string) error {
-	pullOptions := types.ImagePullOptions{All: false}
-	pullProgress, err := dockerClient.ImagePull(ctx, image, pullOptions)
-	defer pullProgress.Close()
+// TagImage Tag the specified image with another reference, replacing any image already tagged with it
+func TagImage(ctx context.Context, dockerClient *client.Client, image string, reference string) error {
+	return dockerClient.ImageTag(ctx, image, reference, types.ImageTagOptions{Force: true})
+}
+
+// PushImage Push the specified image to the registry it is named after, authenticating with the specified
+// encoded credentials, or anonymously if none are specified
+func PushImage(ctx context.Context, dockerClient *client.Client, reference string, registryAuth string) error {
+	if len(registryAuth) < 1 {
+		registryAuth = anonymousRegistryAuth
+	}
 
+	pushProgress, err := dockerClient.ImagePush(ctx, reference, types.ImagePushOptions{RegistryAuth: registryAuth})
 	if err != nil {
 		return err
 	}
+	defer pushProgress.Close()
 
-	jsonmessage.DisplayJSONMessagesStream(pullProgress, os.Stdout, 0, true, nil)
-	_, _ = io.Copy(os.Stdout, pullProgress)
-
-	return nil
+	return jsonmessage.DisplayJSONMessagesStream(pushProgress, log.Output(), 0, true, nil)
 }
 
-// PullImageIfNecessary Pull the specified image if it doesn't already exist
-func PullImageIfNecessary(dockerClient *client.Client, image string) error {
-	ctx := context.Background()
-	_, _, err := dockerClient.ImageInspectWithRaw(ctx, image, false)
+func pullImage(ctx context.Context, dockerClient *client.Client, image string, registryAuth string) error {
+	pullProgress, err := dockerClient.ImagePull(ctx, image, types.ImagePullOptions{RegistryAuth: registryAuth})
 	if err != nil {
-		pullImage(ctx, dockerClient, image)
+		return err
 	}
+	defer pullProgress.Close()
 
-	return nil
+	return jsonmessage.DisplayJSONMessagesStream(pullProgress, log.Output(), 0, true, nil)
+}
+
+// PullImageIfNecessary Pull the specified image as required by the pull policy, authenticating with the
+// specified encoded credentials, or anonymously if none are specified. An image which isn't present can't
+// be used when the policy is to never pull
+func PullImageIfNecessary(ctx context.Context, dockerClient *client.Client, image string, policy v1.PullPolicy,
+	registryAuth string) error {
+	if len(registryAuth) < 1 {
+		registryAuth = anonymousRegistryAuth
+	}
+
+	if policy == v1.PullAlways {
+		return pullImage(ctx, dockerClient, image, registryAuth)
+	}
+
+	exists, err := ImageExists(ctx, dockerClient, image)
+	if err != nil || exists {
+		return err
+	}
+
+	if policy == v1.PullNever {
+		return errors.New("Image " + image + " is not present, and the pull policy doesn't allow pulling it")
+	}
+
+	return pullImage(ctx, dockerClient, image, registryAuth)
 }
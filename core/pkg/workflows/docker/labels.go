@@ -0,0 +1,16 @@
+package docker
+
+// GeneratedLabel Label on the images and containers generated by Sandbox
+const GeneratedLabel = "sbox.generated"
+
+// ProjectLabel Label on the images and containers generated by Sandbox, which records the root of the project
+// they were generated for
+const ProjectLabel = "sbox.project"
+
+// ArtifactLabels Get the labels to put on images and containers generated for the specified project
+func ArtifactLabels(projectRoot string) map[string]string {
+	return map[string]string{
+		GeneratedLabel: "true",
+		ProjectLabel:   projectRoot,
+	}
+}
@@ -0,0 +1,59 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/engine-api/client"
+	"github.com/docker/engine-api/types"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+// NewNetwork Create a network which is created when the first container joins it, and removed when
+// the last container leaves it
+func NewNetwork(dockerClient *client.Client) *Network {
+	return &Network{
+		dockerClient: dockerClient,
+	}
+}
+
+func (n *Network) join() (string, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.members == 0 {
+		name := v1.GenerateNetworkName()
+		response, err := n.dockerClient.NetworkCreate(context.Background(), name, types.NetworkCreate{
+			CheckDuplicate: true,
+			Driver:         "bridge",
+		})
+		if err != nil {
+			return "", err
+		}
+
+		log.Debugf("Created network %v", name)
+		n.id = response.ID
+		n.name = name
+	}
+
+	n.members++
+	return n.name, nil
+}
+
+func (n *Network) leave() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.members--
+	if n.members == 0 {
+		log.Debugf("Removing network %v", n.name)
+
+		err := n.dockerClient.NetworkRemove(context.Background(), n.id)
+		if err != nil {
+			log.Errorf("Error removing network %v: %v", n.name, err.Error())
+		}
+
+		n.id = ""
+		n.name = ""
+	}
+}
@@ -0,0 +1,208 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/engine-api/client"
+	"github.com/docker/engine-api/types"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/errors"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+// Maximum number of images which are pulled at the same time
+const maxConcurrentPulls = 4
+
+// PullRequest An image to pull, along with its pull policy and the encoded credentials to pull it with
+type PullRequest struct {
+	Image        string
+	Policy       v1.PullPolicy
+	RegistryAuth string
+}
+
+type pullError struct {
+	err   error
+	image string
+}
+
+func (e *pullError) Error() string {
+	return "Error pulling image " + e.image + ": " + e.err.Error()
+}
+
+func needsPull(ctx context.Context, dockerClient *client.Client, request *PullRequest) (bool, error) {
+	switch request.Policy {
+	case v1.PullAlways:
+		return true, nil
+	case v1.PullNever:
+		return false, nil
+	}
+
+	exists, err := ImageExists(ctx, dockerClient, request.Image)
+	return !exists, err
+}
+
+func pullWithProgress(ctx context.Context, dockerClient *client.Client, request *PullRequest,
+	messages chan<- jsonmessage.JSONMessage) error {
+	registryAuth := request.RegistryAuth
+	if len(registryAuth) < 1 {
+		registryAuth = anonymousRegistryAuth
+	}
+
+	pullProgress, err := dockerClient.ImagePull(ctx, request.Image, types.ImagePullOptions{RegistryAuth: registryAuth})
+	if err != nil {
+		return err
+	}
+	defer pullProgress.Close()
+
+	decoder := json.NewDecoder(pullProgress)
+	for {
+		var message jsonmessage.JSONMessage
+		err := decoder.Decode(&message)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if message.Error != nil {
+			return message.Error
+		}
+
+		// Progress is shown on a line per image and layer, as the same layer IDs are reported for different
+		// images
+		if len(message.ID) > 0 {
+			message.ID = request.Image + " " + message.ID
+		} else {
+			message.ID = request.Image
+		}
+
+		messages <- message
+	}
+}
+
+func displayPullProgress(messages <-chan jsonmessage.JSONMessage) chan struct{} {
+	reader, writer := io.Pipe()
+	displayed := make(chan struct{})
+
+	go func() {
+		jsonmessage.DisplayJSONMessagesStream(reader, log.Output(), 0, true, nil)
+		_, _ = io.Copy(ioutil.Discard, reader)
+		close(displayed)
+	}()
+
+	go func() {
+		encoder := json.NewEncoder(writer)
+		for message := range messages {
+			encoder.Encode(&message)
+		}
+
+		writer.Close()
+	}()
+
+	return displayed
+}
+
+// PullImages Pull the specified images at the same time, as required by their pull policies, showing their
+// progress together
+func PullImages(ctx context.Context, dockerClient *client.Client, requests []PullRequest) error {
+	var pulls []*PullRequest
+	for i := range requests {
+		request := &requests[i]
+
+		pull, err := needsPull(ctx, dockerClient, request)
+		if err != nil {
+			return err
+		}
+
+		if pull {
+			pulls = append(pulls, request)
+		}
+	}
+
+	if len(pulls) < 1 {
+		return nil
+	}
+
+	log.Infof("Pulling images:")
+
+	messages := make(chan jsonmessage.JSONMessage)
+	displayed := displayPullProgress(messages)
+
+	composite := errors.NewCompositeError()
+	slots := make(chan struct{}, maxConcurrentPulls)
+
+	var lock sync.Mutex
+	var pulled sync.WaitGroup
+
+	for _, request := range pulls {
+		pulled.Add(1)
+		go func(request *PullRequest) {
+			defer pulled.Done()
+
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			err := pullWithProgress(ctx, dockerClient, request, messages)
+			if err != nil {
+				lock.Lock()
+				composite.Append(&pullError{err: err, image: request.Image})
+				lock.Unlock()
+			}
+		}(request)
+	}
+
+	pulled.Wait()
+	close(messages)
+	<-displayed
+
+	return composite.OrNilIfEmpty()
+}
+
+func repositoryName(image string) string {
+	at := strings.Index(image, "@")
+	if at >= 0 {
+		return image[:at]
+	}
+
+	colon := strings.LastIndex(image, ":")
+	if colon > strings.LastIndex(image, "/") {
+		return image[:colon]
+	}
+
+	return image
+}
+
+func normalizedRepository(repository string) string {
+	repository = strings.TrimPrefix(repository, "docker.io/")
+	return strings.TrimPrefix(repository, "library/")
+}
+
+// ImageDigest Get a reference to the specified image by the digest it was pulled at, or an empty reference
+// if it doesn't exist or wasn't pulled from a registry
+func ImageDigest(ctx context.Context, dockerClient *client.Client, image string) (string, error) {
+	inspect, _, err := dockerClient.ImageInspectWithRaw(ctx, image, false)
+	if err != nil {
+		if client.IsErrImageNotFound(err) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	repository := repositoryName(image)
+	for _, repoDigest := range inspect.RepoDigests {
+		at := strings.Index(repoDigest, "@")
+		if at > 0 && normalizedRepository(repoDigest[:at]) == normalizedRepository(repository) {
+			return repository + repoDigest[at:], nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,62 @@
+package docker
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/docker/engine-api/client"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/properties"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+// ContainerListener Listener which listens for container events
+type ContainerListener interface {
+	Created()
+	Started()
+	Container(containerID string)
+	Ready()
+	Done(failed bool, message string)
+}
+
+// ContainerCreationSpec Specification for creating a container
+type ContainerCreationSpec struct {
+	Cleanup          *sync.WaitGroup
+	Command          []string
+	Context          context.Context
+	Environment      *properties.Properties
+	Grace            string
+	Health           *v1.HealthCheck
+	Image            string
+	Labels           map[string]string
+	LogPrefix        string
+	Listener         ContainerListener
+	Network          *Network
+	Output           io.Writer
+	Ports            []v1.Port
+	Readiness        *v1.HealthCheck
+	VariableReceiver func(string, string)
+	Volumes          []v1.Volume
+	WorkflowReceiver func(string)
+}
+
+// Network A user-defined network joined by the containers of a workflow run, so that services can be
+// reached by name
+type Network struct {
+	dockerClient *client.Client
+	id           string
+	lock         sync.Mutex
+	members      int
+	name         string
+}
+
+type containerContext struct {
+	creationSpec      *ContainerCreationSpec
+	dockerClient      *client.Client
+	containerFinished int32
+	containerID       string
+	finished          chan struct{}
+	joinedNetwork     bool
+	logsPrinted       chan struct{}
+	name              string
+}
@@ -0,0 +1,155 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/buffer"
+	executioncontext "github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/processors"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+// NewEmitter Create an emitter which writes events to the specified writer
+func NewEmitter(writer io.Writer) *Emitter {
+	return &Emitter{
+		encoder: json.NewEncoder(writer),
+	}
+}
+
+func (e *Emitter) newEvent(workflow *v1.Workflow, eventType EventType) *Event {
+	run := workflow.Spec.State.ID
+	if e.root != nil {
+		run = e.root.Spec.State.ID
+	}
+
+	return &Event{
+		Version:  SchemaVersion,
+		Type:     eventType,
+		Time:     time.Now().UTC(),
+		Run:      run,
+		Workflow: workflow.Name,
+	}
+}
+
+func (e *Emitter) emit(event *Event) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.encoder.Encode(event)
+}
+
+func stepStatuses(workflow *v1.Workflow) map[string]string {
+	statuses := make(map[string]string)
+
+	selector := workflow.IncrementStepSelector([]int{})
+	for len(selector) > 0 {
+		statuses[workflow.StepPath(selector)] = string(workflow.Select(selector).State.Status)
+		selector = workflow.IncrementStepSelector(selector)
+	}
+
+	return statuses
+}
+
+// WorkflowStarted Emit an event for the start of a workflow
+func (e *Emitter) WorkflowStarted(workflow *v1.Workflow) {
+	if e.root == nil {
+		e.root = workflow
+	}
+
+	e.emit(e.newEvent(workflow, WorkflowStarted))
+}
+
+// ChangeRaised Emit an event for a change raised for a step in a workflow
+func (e *Emitter) ChangeRaised(workflow *v1.Workflow, change *v1.Change) {
+	event := e.newEvent(workflow, Change)
+	event.Change = change.Type
+
+	if len(change.StepSelector) > 0 {
+		event.Step = workflow.StepPath(change.StepSelector)
+		event.Status = string(workflow.Select(change.StepSelector).State.Status)
+	}
+
+	e.emit(event)
+}
+
+func (e *Emitter) logLine(workflow *v1.Workflow, step string, line []byte) {
+	text := strings.TrimRight(string(line), "\r")
+
+	event := e.newEvent(workflow, Log)
+	event.Step = step
+	event.Stream = string(executioncontext.RunOutput)
+	event.Line = text
+	e.emit(event)
+
+	name, value := processors.ExtractVariable(line)
+	if len(name) > 0 {
+		variable := e.newEvent(workflow, Variable)
+		variable.Step = step
+		variable.Name = name
+		variable.Value = value
+		e.emit(variable)
+	}
+}
+
+func (e *Emitter) buildMessage(workflow *v1.Workflow, step string, line []byte) {
+	var message jsonmessage.JSONMessage
+	err := json.Unmarshal(line, &message)
+	if err != nil {
+		return
+	}
+
+	event := e.newEvent(workflow, Build)
+	event.Step = step
+	event.Stream = string(executioncontext.BuildOutput)
+	event.Line = strings.TrimRight(message.Stream, "\r\n")
+	event.Status = message.Status
+
+	if message.Progress != nil && message.Progress.Total > 0 {
+		event.Progress = &Progress{
+			Current: message.Progress.Current,
+			Total:   message.Progress.Total,
+		}
+	}
+
+	if message.Error != nil {
+		event.Error = message.Error.Message
+	} else if len(message.ErrorMessage) > 0 {
+		event.Error = message.ErrorMessage
+	}
+
+	e.emit(event)
+}
+
+// StepOutput Get a writer which emits events for the output of a step
+func (e *Emitter) StepOutput(workflow *v1.Workflow, selector []int, stream executioncontext.OutputStream) io.Writer {
+	step := workflow.StepPath(selector)
+
+	if stream == executioncontext.BuildOutput {
+		return buffer.NewLineBuffer(func(line []byte) {
+			e.buildMessage(workflow, step, line)
+		}, buffer.Unlimited)
+	}
+
+	return buffer.NewLineBuffer(func(line []byte) {
+		e.logLine(workflow, step, line)
+	}, buffer.Unlimited)
+}
+
+// WorkflowFinished Emit an event for the end of a workflow, including the result if it is the workflow that
+// was run
+func (e *Emitter) WorkflowFinished(workflow *v1.Workflow) {
+	eventType := WorkflowFinished
+	if workflow == e.root {
+		eventType = Result
+	}
+
+	event := e.newEvent(workflow, eventType)
+	event.Status = string(workflow.Spec.State.Status)
+	event.Steps = stepStatuses(workflow)
+	e.emit(event)
+}
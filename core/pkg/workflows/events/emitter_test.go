@@ -0,0 +1,138 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	executioncontext "github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+const testWorkflow = `
+steps:
+  - run:
+      name: build
+      image: alpine
+      script: make
+  - compound:
+      name: checks
+      steps:
+        - run:
+            name: test
+            image: alpine
+            script: make test
+`
+
+const buildOutput = `{"stream":"Step 1/2 : FROM alpine\n"}
+{"status":"Downloading","progressDetail":{"current":5,"total":10}}
+Not a message
+{"errorDetail":{"message":"Build failed"},"error":"Build failed"}
+`
+
+func parseTestWorkflow(t *testing.T, name string) *v1.Workflow {
+	workflow, err := v1.ParseWorkflow("/project", name, []byte(testWorkflow))
+	if err != nil {
+		t.Fatalf("Unable to parse workflow: %v", err)
+	}
+
+	return workflow
+}
+
+func writeTestOutput(t *testing.T, writer io.Writer, output string) {
+	_, err := io.WriteString(writer, output)
+	if err != nil {
+		t.Fatalf("Unable to write output: %v", err)
+	}
+}
+
+func readTestEvents(t *testing.T, output *bytes.Buffer) []Event {
+	var events []Event
+
+	decoder := json.NewDecoder(output)
+	for decoder.More() {
+		var event Event
+		err := decoder.Decode(&event)
+		if err != nil {
+			t.Fatalf("Unable to decode event: %v", err)
+		}
+
+		if event.Time.IsZero() {
+			t.Errorf("Expected %v event to have a time, but it didn't", event.Type)
+		}
+
+		event.Time = time.Time{}
+		events = append(events, event)
+	}
+
+	return events
+}
+
+func TestEmitter(t *testing.T) {
+	root := parseTestWorkflow(t, "test")
+	child := parseTestWorkflow(t, "deploy")
+
+	var output bytes.Buffer
+	emitter := NewEmitter(&output)
+
+	emitter.WorkflowStarted(root)
+	emitter.WorkflowStarted(child)
+
+	root.Select([]int{1, 0}).State.Status = v1.StepRunning
+	emitter.ChangeRaised(root, &v1.Change{Type: v1.StepStarted, StepSelector: []int{1, 0}})
+	emitter.ChangeRaised(root, &v1.Change{Type: v1.WorkflowWait})
+
+	writeTestOutput(t, emitter.StepOutput(root, []int{1, 0}, executioncontext.RunOutput),
+		"Testing\r\nvar version=1.2\n")
+	writeTestOutput(t, emitter.StepOutput(root, []int{0}, executioncontext.BuildOutput), buildOutput)
+
+	child.Spec.State.Status = v1.WorkflowSucceeded
+	emitter.WorkflowFinished(child)
+
+	root.Select([]int{0}).State.Status = v1.StepSucceeded
+	root.Select([]int{1, 0}).State.Status = v1.StepFailed
+	root.Spec.State.Status = v1.WorkflowFailed
+	emitter.WorkflowFinished(root)
+
+	run := root.Spec.State.ID
+	expected := []Event{
+		{Version: SchemaVersion, Type: WorkflowStarted, Run: run, Workflow: "test"},
+		{Version: SchemaVersion, Type: WorkflowStarted, Run: run, Workflow: "deploy"},
+		{Version: SchemaVersion, Type: Change, Run: run, Workflow: "test", Step: "checks/test",
+			Change: v1.StepStarted, Status: string(v1.StepRunning)},
+		{Version: SchemaVersion, Type: Change, Run: run, Workflow: "test", Change: v1.WorkflowWait},
+		{Version: SchemaVersion, Type: Log, Run: run, Workflow: "test", Step: "checks/test",
+			Stream: string(executioncontext.RunOutput), Line: "Testing"},
+		{Version: SchemaVersion, Type: Log, Run: run, Workflow: "test", Step: "checks/test",
+			Stream: string(executioncontext.RunOutput), Line: "var version=1.2"},
+		{Version: SchemaVersion, Type: Variable, Run: run, Workflow: "test", Step: "checks/test",
+			Name: "version", Value: "1.2"},
+		{Version: SchemaVersion, Type: Build, Run: run, Workflow: "test", Step: "build",
+			Stream: string(executioncontext.BuildOutput), Line: "Step 1/2 : FROM alpine"},
+		{Version: SchemaVersion, Type: Build, Run: run, Workflow: "test", Step: "build",
+			Stream: string(executioncontext.BuildOutput), Status: "Downloading",
+			Progress: &Progress{Current: 5, Total: 10}},
+		{Version: SchemaVersion, Type: Build, Run: run, Workflow: "test", Step: "build",
+			Stream: string(executioncontext.BuildOutput), Error: "Build failed"},
+		{Version: SchemaVersion, Type: WorkflowFinished, Run: run, Workflow: "deploy",
+			Status: string(v1.WorkflowSucceeded),
+			Steps:  map[string]string{"build": "", "checks/test": ""}},
+		{Version: SchemaVersion, Type: Result, Run: run, Workflow: "test",
+			Status: string(v1.WorkflowFailed),
+			Steps:  map[string]string{"build": string(v1.StepSucceeded), "checks/test": string(v1.StepFailed)}},
+	}
+
+	events := readTestEvents(t, &output)
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %v events, but there were %v: %v", len(expected), len(events), events)
+	}
+
+	for i := range expected {
+		if !reflect.DeepEqual(events[i], expected[i]) {
+			t.Errorf("Expected event %v to be %+v, but it was %+v", i, expected[i], events[i])
+		}
+	}
+}
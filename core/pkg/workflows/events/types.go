@@ -0,0 +1,68 @@
+package events
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+// SchemaVersion Version of the event schema, incremented whenever events change in an incompatible way
+const SchemaVersion = 1
+
+// EventType Type of event
+type EventType string
+
+// WorkflowStarted A workflow was started
+const WorkflowStarted EventType = "workflowStarted"
+
+// Change A change was raised for a step in a workflow
+const Change EventType = "change"
+
+// Build Progress was made building the image for a step
+const Build EventType = "build"
+
+// Log A line of output was produced by a step
+const Log EventType = "log"
+
+// Variable A variable was declared by a step
+const Variable EventType = "variable"
+
+// WorkflowFinished A workflow called by another workflow finished
+const WorkflowFinished EventType = "workflowFinished"
+
+// Result The workflow that was run finished
+const Result EventType = "result"
+
+// Progress Progress of an operation
+type Progress struct {
+	Current int64 `json:"current"`
+	Total   int64 `json:"total"`
+}
+
+// Event An event, emitted as a single JSON object
+type Event struct {
+	Version  int               `json:"version"`
+	Type     EventType         `json:"type"`
+	Time     time.Time         `json:"time"`
+	Run      string            `json:"run"`
+	Workflow string            `json:"workflow"`
+	Step     string            `json:"step,omitempty"`
+	Change   v1.ChangeType     `json:"change,omitempty"`
+	Stream   string            `json:"stream,omitempty"`
+	Line     string            `json:"line,omitempty"`
+	Status   string            `json:"status,omitempty"`
+	Progress *Progress         `json:"progress,omitempty"`
+	Name     string            `json:"name,omitempty"`
+	Value    string            `json:"value,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Steps    map[string]string `json:"steps,omitempty"`
+}
+
+// Emitter Emits events for the progress of workflow executions, as lines of JSON
+type Emitter struct {
+	encoder *json.Encoder
+	lock    sync.Mutex
+	root    *v1.Workflow
+}
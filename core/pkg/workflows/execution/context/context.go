@@ -8,11 +8,17 @@ import (
 )
 
 // NewWorkflowContext Create a new workflow execution context
-func NewWorkflowContext(context context.Context, cancel func(), cleanup *sync.WaitGroup, workflow *v1.Workflow) *WorkflowContext {
+func NewWorkflowContext(
+	context context.Context,
+	cancel func(),
+	cleanup *sync.WaitGroup,
+	observer Observer,
+	workflow *v1.Workflow) *WorkflowContext {
 	return &WorkflowContext{
 		Context:  context,
 		Cancel:   cancel,
 		Cleanup:  cleanup,
+		Observer: observer,
 		Workflow: workflow,
 	}
 }
@@ -47,6 +53,7 @@ func (sc *StepContext) CanProceedToNextStep() bool {
 	if (sc.Change.Type == v1.StepReady && sc.Step.IsServiceWithWait()) ||
 		(sc.Change.Type == v1.StepStarted && (sc.Step == nil || sc.Step.IsAsync())) ||
 		(sc.Change.Type == v1.StepDone && !sc.Step.IsAsync()) ||
+		sc.Change.Type == v1.StepBypassed ||
 		sc.Change.Type == v1.WorkflowWaitDone {
 		if !sc.isAtWorkflowBoundary() {
 			return true
@@ -82,6 +89,7 @@ func (sc *StepContext) IsStepReadyToRun() bool {
 func (sc *StepContext) IsWorkflowComplete() bool {
 	if sc.isAtWorkflowBoundary() {
 		return sc.Change.Type == v1.StepDone ||
+			sc.Change.Type == v1.StepBypassed ||
 			sc.Change.Type == v1.WorkflowWaitDone
 	}
 
@@ -0,0 +1,47 @@
+package context
+
+import (
+	"io"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+// WorkflowStarted Notify all observers that a workflow was started
+func (o Observers) WorkflowStarted(workflow *v1.Workflow) {
+	for _, observer := range o {
+		observer.WorkflowStarted(workflow)
+	}
+}
+
+// ChangeRaised Notify all observers that a change was raised in a workflow
+func (o Observers) ChangeRaised(workflow *v1.Workflow, change *v1.Change) {
+	for _, observer := range o {
+		observer.ChangeRaised(workflow, change)
+	}
+}
+
+// StepOutput Get a writer which writes the output of a step to all observers interested in it
+func (o Observers) StepOutput(workflow *v1.Workflow, selector []int, stream OutputStream) io.Writer {
+	var writers []io.Writer
+	for _, observer := range o {
+		writer := observer.StepOutput(workflow, selector, stream)
+		if writer != nil {
+			writers = append(writers, writer)
+		}
+	}
+
+	if len(writers) == 0 {
+		return nil
+	} else if len(writers) == 1 {
+		return writers[0]
+	}
+
+	return io.MultiWriter(writers...)
+}
+
+// WorkflowFinished Notify all observers that a workflow has finished
+func (o Observers) WorkflowFinished(workflow *v1.Workflow) {
+	for _, observer := range o {
+		observer.WorkflowFinished(workflow)
+	}
+}
@@ -2,11 +2,32 @@ package context
 
 import (
 	"context"
+	"io"
 	"sync"
 
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
 )
 
+// OutputStream A stream of output produced by a step
+type OutputStream string
+
+// BuildOutput Output of the image build for a step, as a stream of Docker JSON messages
+const BuildOutput OutputStream = "build"
+
+// RunOutput Output of the container running a step
+const RunOutput OutputStream = "run"
+
+// Observer Observes the progress of workflow executions
+type Observer interface {
+	WorkflowStarted(workflow *v1.Workflow)
+	ChangeRaised(workflow *v1.Workflow, change *v1.Change)
+	StepOutput(workflow *v1.Workflow, selector []int, stream OutputStream) io.Writer
+	WorkflowFinished(workflow *v1.Workflow)
+}
+
+// Observers A set of observers which are all notified together
+type Observers []Observer
+
 // StepContext Context for a step execution
 type StepContext struct {
 	WorkflowContext  *WorkflowContext
@@ -22,5 +43,6 @@ type WorkflowContext struct {
 	Cancel   func()
 	Cleanup  *sync.WaitGroup
 	Context  context.Context
+	Observer Observer
 	Workflow *v1.Workflow
 }
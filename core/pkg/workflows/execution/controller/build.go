@@ -6,14 +6,37 @@ import (
 	executioncontext "github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/image"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/preparation"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+func startStep(step *v1.WorkflowStep) {
+	if step != nil && step.State.Started == nil {
+		started := metav1.Now()
+
+		step.State.Status = v1.StepRunning
+		step.State.Started = &started
+	}
+}
+
 func (c *executionController) buildStepImageAndTransitionNext(sc *executioncontext.StepContext) error {
+	if sc.NextStep.IsSkipped() {
+		log.Infof("Skipping step %v", sc.NextStep.StepName(sc.NextStepSelector))
+		return c.transitionNext(sc, stepBypassedTransition)
+	}
+
+	startStep(sc.NextStep)
+
 	err := preparation.PrepareStepIfNecessary(sc.WorkflowContext.Workflow, sc.NextStep, sc.NextStepSelector)
 	if err != nil {
+		finishStep(sc.NextStep, v1.StepFailed)
 		return err
 	}
 
+	prepared := metav1.Now()
+	sc.NextStep.State.Timings.Prepared = &prepared
+
 	if sc.NextStep.RequiresBuild() {
 		err := image.BuildStepImage(c.coordinator, sc)
 		if err != nil {
@@ -23,6 +46,7 @@ func (c *executionController) buildStepImageAndTransitionNext(sc *executionconte
 
 			err = shouldIgnoreFailure(sc.WorkflowContext.Workflow, sc.NextStep, sc.NextStepSelector, err)
 			if err != nil {
+				finishStep(sc.NextStep, v1.StepFailed)
 				return err
 			}
 		}
@@ -1,8 +1,6 @@
 package controller
 
 import (
-	"fmt"
-
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/files"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
@@ -20,21 +18,40 @@ func (c *executionController) executeChild(sc *context.StepContext, child *v1.Wo
 	}
 
 	child.Spec.State.Variables = filterVariables(include, exclude, sc.WorkflowContext.Workflow.Spec.State.Variables)
+	child.Spec.State.Pins = sc.WorkflowContext.Workflow.Spec.State.Pins
 
 	go func() {
 		c.Execute(sc.WorkflowContext.Context, child)
 		log.Debugf("Finished called workflow")
-		c.transitionNext(sc, workflowWaitDoneTransition)
+		c.childWorkflowDone(sc, child)
 	}()
 
 	return c.transitionNext(sc, workflowWaitTransition)
 }
 
+func (c *executionController) childWorkflowDone(sc *context.StepContext, child *v1.Workflow) {
+	stepName := sc.Step.StepName(sc.StepSelector)
+	failed := child.Spec.State.Status == v1.WorkflowFailed
+
+	if failed {
+		if !areFailuresIgnored(sc.WorkflowContext.Workflow, sc.Step, sc.StepSelector) {
+			c.stepFailed(sc)
+			return
+		}
+
+		log.Infof("Step %v failed, but ignoring and continuing", stepName)
+	}
+
+	transition := workflowWaitDoneTransition{failed: failed}
+	c.transitionNext(sc, transition.transition)
+	c.stepFinished(sc)
+}
+
 func (c *executionController) callGeneratedWorkflow(sc *context.StepContext) error {
 	step := sc.Step
 	stepName := step.StepName(sc.Change.StepSelector)
 
-	fmt.Println("Running generated workflow:")
+	log.Infof("Running generated workflow:")
 
 	content := []byte(step.State.GeneratedWorkflow)
 	workflow, err := v1.ParseWorkflow(sc.WorkflowContext.Workflow.Spec.State.ProjectRoot, stepName, content)
@@ -49,7 +66,7 @@ func (c *executionController) callExternalWorkflow(sc *context.StepContext) erro
 	step := sc.Step
 	stepName := step.StepName(sc.Change.StepSelector)
 
-	fmt.Println("Running step " + stepName + ":")
+	log.Infof("Running step %v:", stepName)
 
 	workflow, err := files.ReadWorkflow(sc.Step.External.Workflow)
 	if err != nil {
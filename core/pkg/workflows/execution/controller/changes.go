@@ -16,6 +16,7 @@ func (c *executionController) processChangeAndTransitionNext(sc *executioncontex
 		return c.callGeneratedWorkflow(sc)
 	case sc.IsWorkflowComplete():
 		log.Debugf("Workflow completed")
+		sc.WorkflowContext.Workflow.Spec.State.Status = v1.WorkflowSucceeded
 		sc.WorkflowContext.Cancel()
 		return nil
 	case sc.IsCompoundStepComplete():
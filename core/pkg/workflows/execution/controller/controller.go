@@ -2,36 +2,37 @@ package controller
 
 import (
 	"context"
-	"fmt"
 	"sync"
 
 	executioncontext "github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
 	"github.com/stackfoundation/sandbox/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// NewController Create a new workflow execution controller
-func NewController() (Controller, error) {
-	coordinator, err := coordinator.NewCoordinator()
-	if err != nil {
-		return nil, err
-	}
-
+// NewController Create a new workflow execution controller, which runs steps with the specified coordinator and
+// notifies the specified observers
+func NewController(coordinator coordinator.Coordinator, observers ...executioncontext.Observer) Controller {
 	return &executionController{
 		coordinator:        coordinator,
+		observers:          observers,
 		pendingTransitions: make(chan pendingTransition),
-	}, nil
+		scheduler:          newScheduler(),
+	}
 }
 
 func (c *executionController) processTransitionsAndChanges(wc *executioncontext.WorkflowContext) {
 	log.Debugf("Running workflow \"%v\"", wc.Workflow.Name)
 	for {
 		c.processTransitions(wc)
+		if wc.Context.Err() != nil {
+			return
+		}
+
 		err := c.processNextChange(wc)
 		if err != nil {
-			fmt.Println(err.Error())
-			wc.Cancel()
+			failWorkflow(wc, err)
 			return
 		}
 
@@ -44,16 +45,67 @@ func (c *executionController) processTransitionsAndChanges(wc *executioncontext.
 	}
 }
 
+func failWorkflow(wc *executioncontext.WorkflowContext, err error) {
+	log.Errorf("%v", err.Error())
+	wc.Workflow.Spec.State.Status = v1.WorkflowFailed
+	wc.Cancel()
+}
+
+func startWorkflow(wc *executioncontext.WorkflowContext) {
+	started := metav1.Now()
+
+	state := &wc.Workflow.Spec.State
+	state.Status = v1.WorkflowRunning
+	state.Started = &started
+
+	wc.Observer.WorkflowStarted(wc.Workflow)
+}
+
+func finishRunningSteps(steps []v1.WorkflowStep, succeeded bool) {
+	for i := range steps {
+		step := &steps[i]
+
+		if step.Compound != nil {
+			finishRunningSteps(step.Compound.Steps, succeeded)
+		} else if step.State.Status == v1.StepRunning {
+			if succeeded && step.Service != nil {
+				finishStep(step, v1.StepSucceeded)
+			} else {
+				finishStep(step, v1.StepCancelled)
+			}
+		}
+	}
+}
+
+func finishWorkflow(wc *executioncontext.WorkflowContext) {
+	finished := metav1.Now()
+
+	state := &wc.Workflow.Spec.State
+	if state.Status == v1.WorkflowRunning {
+		state.Status = v1.WorkflowCancelled
+	}
+
+	finishRunningSteps(wc.Workflow.Spec.Steps, state.Status == v1.WorkflowSucceeded)
+
+	state.Finished = &finished
+
+	wc.Observer.WorkflowFinished(wc.Workflow)
+}
+
 // Execute Execute the specified workflow
 func (c *executionController) Execute(ctx context.Context, workflow *v1.Workflow) {
 	cleanup := &sync.WaitGroup{}
 
 	completion, cancel := context.WithCancel(ctx)
-	wc := executioncontext.NewWorkflowContext(completion, cancel, cleanup, workflow)
+	wc := executioncontext.NewWorkflowContext(completion, cancel, cleanup, c.observers, workflow)
 
+	startWorkflow(wc)
 	c.processTransitionsAndChanges(wc)
 
 	log.Debugf("Performing cleanup...")
 	cleanup.Wait()
 	log.Debugf("Finished cleanup")
+
+	finishWorkflow(wc)
+	c.scheduler.forget(workflow)
 }
@@ -0,0 +1,412 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator/fake"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/history"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/resume"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testTimeout = 10 * time.Second
+
+func parseTestWorkflow(t *testing.T, content string) *v1.Workflow {
+	workflow, err := v1.ParseWorkflow("/project", "test", []byte(content))
+	if err != nil {
+		t.Fatalf("Unable to parse workflow: %v", err)
+	}
+
+	return workflow
+}
+
+func executeTestWorkflow(t *testing.T, workflow *v1.Workflow, script *fake.Script) *fake.Coordinator {
+	coordinator := fake.NewCoordinator(script)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	NewController(coordinator).Execute(ctx, workflow)
+	if ctx.Err() == context.DeadlineExceeded {
+		t.Fatalf("Workflow did not finish within %v", testTimeout)
+	}
+
+	return coordinator
+}
+
+func stepStatus(workflow *v1.Workflow, name string) v1.StepStatus {
+	selector := workflow.FindStep(name)
+	if selector == nil {
+		return ""
+	}
+
+	return workflow.Select(selector).State.Status
+}
+
+func TestStepsRunInOrder(t *testing.T) {
+	workflow := parseTestWorkflow(t, `
+steps:
+  - run:
+      name: first
+      image: alpine
+      script: echo first
+  - run:
+      name: second
+      image: alpine
+      script: echo second
+  - run:
+      name: third
+      image: alpine
+      script: echo third
+`)
+
+	coordinator := executeTestWorkflow(t, workflow, nil)
+
+	if workflow.Spec.State.Status != v1.WorkflowSucceeded {
+		t.Errorf("Expected workflow to succeed, but it was %v", workflow.Spec.State.Status)
+	}
+
+	expected := []string{"first", "second", "third"}
+	if runs := coordinator.Runs(); !reflect.DeepEqual(runs, expected) {
+		t.Errorf("Expected steps to run in order %v, but they ran in order %v", expected, runs)
+	}
+}
+
+func TestVariablesDeclaredInOutput(t *testing.T) {
+	workflow := parseTestWorkflow(t, `
+steps:
+  - run:
+      name: version
+      image: alpine
+      script: echo var version=1.2
+`)
+
+	executeTestWorkflow(t, workflow, &fake.Script{
+		Steps: map[string]fake.StepScript{
+			"version": {Output: []string{"Calculating version", "var version=1.2"}},
+		},
+	})
+
+	if value := workflow.Spec.State.Variables.Map()["version"]; value != "1.2" {
+		t.Errorf("Expected variable version to be \"1.2\", but it was \"%v\"", value)
+	}
+}
+
+func TestFailureStopsWorkflow(t *testing.T) {
+	workflow := parseTestWorkflow(t, `
+steps:
+  - run:
+      name: test
+      image: alpine
+      script: exit 1
+  - run:
+      name: deploy
+      image: alpine
+      script: echo deploy
+`)
+
+	coordinator := executeTestWorkflow(t, workflow, &fake.Script{
+		Steps: map[string]fake.StepScript{
+			"test": {Failure: "Tests failed"},
+		},
+	})
+
+	if workflow.Spec.State.Status != v1.WorkflowFailed {
+		t.Errorf("Expected workflow to fail, but it was %v", workflow.Spec.State.Status)
+	}
+
+	if status := stepStatus(workflow, "test"); status != v1.StepFailed {
+		t.Errorf("Expected step test to fail, but it was %v", status)
+	}
+
+	for _, run := range coordinator.Runs() {
+		if run == "deploy" {
+			t.Errorf("Expected step deploy not to run after step test failed")
+		}
+	}
+}
+
+func TestIgnoredFailure(t *testing.T) {
+	workflow := parseTestWorkflow(t, `
+steps:
+  - run:
+      name: lint
+      image: alpine
+      script: exit 1
+      ignoreFailure: true
+  - run:
+      name: build
+      image: alpine
+      script: echo build
+`)
+
+	coordinator := executeTestWorkflow(t, workflow, &fake.Script{
+		Steps: map[string]fake.StepScript{
+			"lint": {Failure: "Lint errors"},
+		},
+	})
+
+	if workflow.Spec.State.Status != v1.WorkflowSucceeded {
+		t.Errorf("Expected workflow to succeed, but it was %v", workflow.Spec.State.Status)
+	}
+
+	if status := stepStatus(workflow, "lint"); status != v1.StepFailureIgnored {
+		t.Errorf("Expected failure of step lint to be ignored, but it was %v", status)
+	}
+
+	expected := []string{"lint", "build"}
+	if runs := coordinator.Runs(); !reflect.DeepEqual(runs, expected) {
+		t.Errorf("Expected steps %v to run, but %v ran", expected, runs)
+	}
+}
+
+func TestBuildFailure(t *testing.T) {
+	workflow := parseTestWorkflow(t, `
+steps:
+  - run:
+      name: compile
+      image: alpine
+      script: make
+`)
+
+	coordinator := executeTestWorkflow(t, workflow, &fake.Script{
+		Steps: map[string]fake.StepScript{
+			"compile": {BuildFailure: "Unable to pull alpine"},
+		},
+	})
+
+	if workflow.Spec.State.Status != v1.WorkflowFailed {
+		t.Errorf("Expected workflow to fail, but it was %v", workflow.Spec.State.Status)
+	}
+
+	if runs := coordinator.Runs(); len(runs) > 0 {
+		t.Errorf("Expected no steps to run after the build failed, but %v ran", runs)
+	}
+}
+
+func TestGeneratedWorkflow(t *testing.T) {
+	workflow := parseTestWorkflow(t, `
+steps:
+  - generator:
+      name: generate
+      image: alpine
+      script: ./generate.sh
+  - run:
+      name: after
+      image: alpine
+      script: echo after
+`)
+
+	coordinator := executeTestWorkflow(t, workflow, &fake.Script{
+		Steps: map[string]fake.StepScript{
+			"generate": {
+				Output: []string{
+					"workflow {",
+					"steps:",
+					"  - run:",
+					"      name: generated",
+					"      image: alpine",
+					"      script: echo generated",
+					"}",
+				},
+			},
+		},
+	})
+
+	if workflow.Spec.State.Status != v1.WorkflowSucceeded {
+		t.Errorf("Expected workflow to succeed, but it was %v", workflow.Spec.State.Status)
+	}
+
+	expected := []string{"generate", "generated", "after"}
+	if runs := coordinator.Runs(); !reflect.DeepEqual(runs, expected) {
+		t.Errorf("Expected steps to run in order %v, but they ran in order %v", expected, runs)
+	}
+}
+
+func TestPublishedImages(t *testing.T) {
+	workflow := parseTestWorkflow(t, `
+steps:
+  - run:
+      name: compile
+      image: alpine
+      script: make
+      publish:
+        tags:
+          - example/app:latest
+  - run:
+      name: version
+      image: alpine
+      cache: true
+      script: echo var version=1.2
+      publish:
+        tags:
+          - example/version:latest
+  - compound:
+      steps:
+        - run:
+            name: package
+            image: alpine
+            script: make package
+  - publish:
+      name: release
+      step: package
+      tags:
+        - registry.example.com/app:${version}
+      push: true
+      username: user
+      password: secret
+`)
+
+	coordinator := executeTestWorkflow(t, workflow, &fake.Script{
+		Steps: map[string]fake.StepScript{
+			"version": {Output: []string{"var version=1.2"}},
+		},
+	})
+
+	if workflow.Spec.State.Status != v1.WorkflowSucceeded {
+		t.Errorf("Expected workflow to succeed, but it was %v", workflow.Spec.State.Status)
+	}
+
+	expected := []string{"example/app:latest", "example/version:latest", "registry.example.com/app:1.2"}
+	if tags := coordinator.Tags(); !reflect.DeepEqual(tags, expected) {
+		t.Errorf("Expected images to be tagged %v, but they were tagged %v", expected, tags)
+	}
+
+	expected = []string{"registry.example.com/app:1.2"}
+	if pushes := coordinator.Pushes(); !reflect.DeepEqual(pushes, expected) {
+		t.Errorf("Expected images %v to be pushed, but %v were pushed", expected, pushes)
+	}
+
+	// The containers of steps whose scripts ran in them are committed, but not the container of the cached
+	// step, whose script ran as part of its image build
+	expected = []string{"fake-compile", "fake-package"}
+	if commits := coordinator.Commits(); !reflect.DeepEqual(commits, expected) {
+		t.Errorf("Expected containers %v to be committed, but %v were committed", expected, commits)
+	}
+}
+
+func TestCachedImageDistributedAfterFailedPush(t *testing.T) {
+	content := `
+steps:
+  - run:
+      name: build
+      image: alpine
+      script: make
+`
+
+	coordinator := fake.NewCoordinator(&fake.Script{
+		Steps: map[string]fake.StepScript{
+			"build": {PushFailures: 1},
+		},
+	})
+
+	execute := func() *v1.Workflow {
+		workflow := parseTestWorkflow(t, content)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+		defer cancel()
+
+		NewController(coordinator).Execute(ctx, workflow)
+		if ctx.Err() == context.DeadlineExceeded {
+			t.Fatalf("Workflow did not finish within %v", testTimeout)
+		}
+
+		return workflow
+	}
+
+	first := execute()
+	if first.Spec.State.Status != v1.WorkflowFailed {
+		t.Errorf("Expected the first run to fail when the image can't be pushed, but it was %v",
+			first.Spec.State.Status)
+	}
+
+	if runs := coordinator.Runs(); len(runs) > 0 {
+		t.Errorf("Expected no steps to run when the image can't be pushed, but %v ran", runs)
+	}
+
+	// The image is found in the cache by the second run, which pushes it again instead of assuming it was
+	// pushed when it was built
+	second := execute()
+	if second.Spec.State.Status != v1.WorkflowSucceeded {
+		t.Errorf("Expected the second run to succeed, but it was %v", second.Spec.State.Status)
+	}
+
+	expected := []string{"build"}
+	if builds := coordinator.Builds(); !reflect.DeepEqual(builds, expected) {
+		t.Errorf("Expected images for steps %v to be built, but images for %v were built", expected, builds)
+	}
+
+	if distributions := coordinator.Distributions(); !reflect.DeepEqual(distributions, expected) {
+		t.Errorf("Expected images for steps %v to be pushed, but images for %v were pushed", expected, distributions)
+	}
+
+	if runs := coordinator.Runs(); !reflect.DeepEqual(runs, expected) {
+		t.Errorf("Expected steps %v to run, but %v ran", expected, runs)
+	}
+}
+
+func TestResumedRunReattachesRunningSteps(t *testing.T) {
+	content := `
+steps:
+  - run:
+      name: first
+      image: alpine
+      script: echo first
+  - run:
+      name: second
+      image: alpine
+      script: echo second
+  - run:
+      name: third
+      image: alpine
+      script: echo third
+`
+
+	// The previous run stopped while step second was running, after step first had finished
+	started := metav1.Now()
+	previous := parseTestWorkflow(t, content)
+
+	first := &previous.Spec.Steps[0].State
+	first.GeneratedImage = "step:first"
+	first.Status = v1.StepSucceeded
+	first.Started = &started
+	first.Finished = &started
+
+	second := &previous.Spec.Steps[1].State
+	second.GeneratedImage = "step:second"
+	second.Status = v1.StepRunning
+	second.Started = &started
+
+	workflow := parseTestWorkflow(t, content)
+	resume.Reattach(workflow, &history.Run{ID: previous.Spec.State.ID, Spec: previous.Spec})
+
+	coordinator := executeTestWorkflow(t, workflow, nil)
+
+	if workflow.Spec.State.Status != v1.WorkflowSucceeded {
+		t.Errorf("Expected workflow to succeed, but it was %v", workflow.Spec.State.Status)
+	}
+
+	// Steps which finished are only skipped when they are restored, which step first wasn't
+	expected := []string{"first", "third"}
+	if builds := coordinator.Builds(); !reflect.DeepEqual(builds, expected) {
+		t.Errorf("Expected images for steps %v to be built, but images for %v were built", expected, builds)
+	}
+
+	expected = []string{"second"}
+	if reattaches := coordinator.Reattaches(); !reflect.DeepEqual(reattaches, expected) {
+		t.Errorf("Expected steps %v to be reattached to, but %v were", expected, reattaches)
+	}
+
+	expected = []string{"first", "second", "third"}
+	if runs := coordinator.Runs(); !reflect.DeepEqual(runs, expected) {
+		t.Errorf("Expected steps to run in order %v, but they ran in order %v", expected, runs)
+	}
+
+	if image := workflow.Spec.Steps[1].State.GeneratedImage; image != "step:second" {
+		t.Errorf("Expected step second to keep its image step:second, but it was %v", image)
+	}
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	executioncontext "github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
 	"github.com/stackfoundation/sandbox/log"
 )
@@ -17,6 +18,22 @@ func areFailuresIgnored(workflow *v1.Workflow, step *v1.WorkflowStep, stepSelect
 	return true
 }
 
+func (c *executionController) reportFailure(sc *executioncontext.StepContext) {
+	log.Infof("Step %v failed, aborting!", sc.Step.StepName(sc.StepSelector))
+	c.transitionNext(sc, stepAbortedTransition)
+}
+
+func (c *executionController) stepFailed(sc *executioncontext.StepContext) {
+	if c.scheduler.fail(sc) {
+		c.reportFailure(sc)
+		return
+	}
+
+	log.Infof("Step %v failed, waiting for the parallel steps which are running to finish",
+		sc.Step.StepName(sc.StepSelector))
+	c.transitionNext(sc, stepFailedTransition)
+}
+
 func shouldIgnoreFailure(workflow *v1.Workflow, step *v1.WorkflowStep, stepSelector []int, err error) error {
 	if !areFailuresIgnored(workflow, step, stepSelector) {
 		return err
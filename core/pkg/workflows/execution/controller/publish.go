@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"context"
+
+	executioncontext "github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/publish"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+func (c *executionController) publishDone(sc *executioncontext.StepContext, err error) {
+	failed := err != nil
+	if failed {
+		if err == context.Canceled {
+			return
+		}
+
+		if !areFailuresIgnored(sc.WorkflowContext.Workflow, sc.Step, sc.StepSelector) {
+			log.Errorf("%v", err.Error())
+			c.stepFailed(sc)
+			return
+		}
+
+		log.Infof("Step %v failed, but ignoring and continuing", sc.Step.StepName(sc.StepSelector))
+	}
+
+	transition := stepDoneTransition{failed: failed}
+	c.transitionNext(sc, transition.transition)
+	c.stepFinished(sc)
+}
+
+func (c *executionController) publishAndTransitionNext(sc *executioncontext.StepContext) error {
+	log.Infof("Running step %v:", sc.Step.StepName(sc.StepSelector))
+
+	go func() {
+		err := publish.PublishStep(c.coordinator, sc)
+		c.publishDone(sc, err)
+	}()
+
+	return c.transitionNext(sc, stepStartedTransition)
+}
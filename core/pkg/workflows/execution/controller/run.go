@@ -2,40 +2,66 @@ package controller
 
 import (
 	"context"
-	"fmt"
 
 	executioncontext "github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/preparation"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/publish"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/run"
+	"github.com/stackfoundation/sandbox/log"
 )
 
-func (l *runListener) Ready(sc *executioncontext.StepContext) {
-	l.controller.transitionNext(sc, stepReadyTransition)
+func (l *runListener) Ready(sc *executioncontext.StepContext, r *run.Result) {
+	if sc.Step.Service != nil {
+		err := publish.PublishStepImage(l.controller.coordinator, sc, "")
+		if err != nil {
+			r.Message = err.Error()
+			l.Failed(sc, r)
+			return
+		}
+	}
+
+	transition := stepReadyTransition{timings: r.Timings}
+	l.controller.transitionNext(sc, transition.transition)
 }
 
-func (l *runListener) Done(sc *executioncontext.StepContext, r *run.Result) {
+func (l *runListener) done(sc *executioncontext.StepContext, r *run.Result, failed bool) {
 	transition := stepDoneTransition{
+		failed:             failed,
 		variables:          r.Variables,
 		generatedContainer: r.Container,
 		generatedWorkfow:   r.Workflow,
+		timings:            r.Timings,
 	}
 
 	l.controller.transitionNext(sc, transition.transition)
+	l.controller.stepFinished(sc)
+}
+
+func (l *runListener) Done(sc *executioncontext.StepContext, r *run.Result) {
+	if sc.Step.Run != nil {
+		err := publish.PublishStepImage(l.controller.coordinator, sc, r.Container)
+		if err != nil {
+			r.Message = err.Error()
+			l.Failed(sc, r)
+			return
+		}
+	}
+
+	l.done(sc, r, false)
 }
 
 func (l *runListener) Failed(sc *executioncontext.StepContext, r *run.Result) {
 	if !areFailuresIgnored(sc.WorkflowContext.Workflow, sc.Step, sc.StepSelector) {
 		if len(r.Message) > 0 {
-			fmt.Println(r.Message)
+			log.Infof("%v", r.Message)
 		}
 
-		fmt.Printf("Step %v failed, aborting!\n", sc.Step.StepName(sc.StepSelector))
-		sc.WorkflowContext.Cancel()
+		l.controller.stepFailed(sc)
 		return
 	}
 
-	fmt.Printf("Step %v failed, but ignoring and continuing\n", sc.Step.StepName(sc.StepSelector))
-	l.Done(sc, r)
+	log.Infof("Step %v failed, but ignoring and continuing", sc.Step.StepName(sc.StepSelector))
+	l.done(sc, r, true)
 }
 
 func (c *executionController) runPodStepAndTransitionNext(sc *executioncontext.StepContext) error {
@@ -46,16 +72,49 @@ func (c *executionController) runPodStepAndTransitionNext(sc *executioncontext.S
 		}
 
 		err = shouldIgnoreFailure(sc.WorkflowContext.Workflow, sc.Step, sc.StepSelector, err)
-	}
+		if err != nil {
+			return err
+		}
 
-	if err != nil {
-		return c.transitionNext(sc, (&stepDoneTransition{}).transition)
+		c.transitionNext(sc, (&stepDoneTransition{failed: true}).transition)
+		c.stepFinished(sc)
+		return nil
 	}
 
 	return c.transitionNext(sc, stepStartedTransition)
 }
 
+func (c *executionController) stepFinished(sc *executioncontext.StepContext) {
+	next, failure := c.scheduler.finish(sc)
+	if failure != nil {
+		c.reportFailure(failure)
+	} else if next != nil {
+		c.transitionNext(next, c.startQueuedStep)
+	}
+}
+
+func (c *executionController) startQueuedStep(sc *executioncontext.StepContext) {
+	err := c.runScheduledStepAndTransitionNext(sc)
+	if err != nil {
+		failWorkflow(sc.WorkflowContext, err)
+	}
+}
+
 func (c *executionController) runStepAndTransitionNext(sc *executioncontext.StepContext) error {
+	started, queued := c.scheduler.start(sc)
+	if !started {
+		if queued {
+			log.Infof("Waiting for a parallel step to finish before running step %v",
+				sc.Step.StepName(sc.StepSelector))
+		}
+
+		return c.transitionNext(sc, consumeTransition)
+	}
+
+	return c.runScheduledStepAndTransitionNext(sc)
+}
+
+func (c *executionController) runScheduledStepAndTransitionNext(sc *executioncontext.StepContext) error {
 	err := preparation.PrepareStepIfNecessary(sc.WorkflowContext.Workflow, sc.Step, sc.StepSelector)
 	if err != nil {
 		return err
@@ -63,6 +122,8 @@ func (c *executionController) runStepAndTransitionNext(sc *executioncontext.Step
 
 	if sc.Step.RequiresBuild() {
 		return c.runPodStepAndTransitionNext(sc)
+	} else if sc.Step.Publish != nil {
+		return c.publishAndTransitionNext(sc)
 	}
 
 	return c.callExternalWorkflow(sc)
@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+
+	executioncontext "github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+type parallelScope struct {
+	queued  []*executioncontext.StepContext
+	running map[string]bool
+}
+
+type workflowSchedule struct {
+	failure *executioncontext.StepContext
+	scopes  map[string]*parallelScope
+}
+
+type scheduler struct {
+	lock      sync.Mutex
+	workflows map[*v1.Workflow]*workflowSchedule
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{
+		workflows: make(map[*v1.Workflow]*workflowSchedule),
+	}
+}
+
+func maxParallel(workflow *v1.Workflow, selector []int) int {
+	parent := workflow.Parent(selector)
+	if parent != nil && parent.Compound.MaxParallel > 0 {
+		return parent.Compound.MaxParallel
+	}
+
+	return workflow.Spec.MaxParallel
+}
+
+func isFailFast(workflow *v1.Workflow, selector []int) bool {
+	parent := workflow.Parent(selector)
+	if parent != nil && parent.Compound.FailFast != nil {
+		return *parent.Compound.FailFast
+	}
+
+	if workflow.Spec.FailFast != nil {
+		return *workflow.Spec.FailFast
+	}
+
+	return true
+}
+
+func (s *scheduler) schedule(workflow *v1.Workflow) *workflowSchedule {
+	schedule, ok := s.workflows[workflow]
+	if !ok {
+		schedule = &workflowSchedule{
+			scopes: make(map[string]*parallelScope),
+		}
+		s.workflows[workflow] = schedule
+	}
+
+	return schedule
+}
+
+func (s *workflowSchedule) scope(selector []int) *parallelScope {
+	key := fmt.Sprint(selector[:len(selector)-1])
+
+	scope, ok := s.scopes[key]
+	if !ok {
+		scope = &parallelScope{
+			running: make(map[string]bool),
+		}
+		s.scopes[key] = scope
+	}
+
+	return scope
+}
+
+func (s *workflowSchedule) running() int {
+	running := 0
+	for _, scope := range s.scopes {
+		running += len(scope.running)
+	}
+
+	return running
+}
+
+// start Start the step in context, unless a failure is waiting to be reported, or the step is a parallel
+// step and the limit of parallel steps running alongside it has been reached, in which case it is queued
+func (s *scheduler) start(sc *executioncontext.StepContext) (started bool, queued bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	schedule := s.schedule(sc.WorkflowContext.Workflow)
+	if schedule.failure != nil {
+		return false, false
+	}
+
+	if !sc.Step.IsParallel() {
+		return true, false
+	}
+
+	scope := schedule.scope(sc.StepSelector)
+
+	limit := maxParallel(sc.WorkflowContext.Workflow, sc.StepSelector)
+	if limit > 0 && len(scope.running) >= limit {
+		scope.queued = append(scope.queued, sc)
+		return false, true
+	}
+
+	scope.running[fmt.Sprint(sc.StepSelector)] = true
+	return true, false
+}
+
+// finish Record that the step in context finished. Returns a queued step that can now start, or a failure
+// that can now be reported, once no parallel steps are running
+func (s *scheduler) finish(sc *executioncontext.StepContext) (*executioncontext.StepContext, *executioncontext.StepContext) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !sc.Step.IsParallel() {
+		return nil, nil
+	}
+
+	schedule := s.schedule(sc.WorkflowContext.Workflow)
+	scope := schedule.scope(sc.StepSelector)
+
+	key := fmt.Sprint(sc.StepSelector)
+	if !scope.running[key] {
+		return nil, nil
+	}
+
+	delete(scope.running, key)
+
+	if schedule.failure != nil {
+		if schedule.running() > 0 {
+			return nil, nil
+		}
+
+		return nil, schedule.failure
+	}
+
+	if len(scope.queued) > 0 {
+		next := scope.queued[0]
+		scope.queued = scope.queued[1:]
+
+		scope.running[fmt.Sprint(next.StepSelector)] = true
+		return next, nil
+	}
+
+	return nil, nil
+}
+
+// fail Record that the step in context failed. Returns true if the failure should be reported right away,
+// or false if it should be reported once the parallel steps which are still running finish
+func (s *scheduler) fail(sc *executioncontext.StepContext) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	schedule := s.schedule(sc.WorkflowContext.Workflow)
+	if sc.Step.IsParallel() {
+		delete(schedule.scope(sc.StepSelector).running, fmt.Sprint(sc.StepSelector))
+	}
+
+	if isFailFast(sc.WorkflowContext.Workflow, sc.StepSelector) || schedule.running() < 1 {
+		return true
+	}
+
+	if schedule.failure == nil {
+		schedule.failure = sc
+	}
+
+	for _, scope := range schedule.scopes {
+		scope.queued = nil
+	}
+
+	return false
+}
+
+func (s *scheduler) forget(workflow *v1.Workflow) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.workflows, workflow)
+}
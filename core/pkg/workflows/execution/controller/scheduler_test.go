@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator/fake"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+func TestMaxParallel(t *testing.T) {
+	tests := []struct {
+		name       string
+		workflow   string
+		steps      []string
+		maxRunning int
+	}{
+		{
+			name: "unlimited",
+			workflow: `
+steps:
+  - run:
+      name: one
+      image: alpine
+      parallel: true
+  - run:
+      name: two
+      image: alpine
+      parallel: true
+  - run:
+      name: three
+      image: alpine
+      parallel: true
+`,
+			steps:      []string{"one", "two", "three"},
+			maxRunning: 3,
+		},
+		{
+			name: "workflow limit",
+			workflow: `
+maxParallel: 2
+steps:
+  - run:
+      name: one
+      image: alpine
+      parallel: true
+  - run:
+      name: two
+      image: alpine
+      parallel: true
+  - run:
+      name: three
+      image: alpine
+      parallel: true
+  - run:
+      name: four
+      image: alpine
+      parallel: true
+`,
+			steps:      []string{"one", "two", "three", "four"},
+			maxRunning: 2,
+		},
+		{
+			name: "compound limit overrides workflow limit",
+			workflow: `
+maxParallel: 3
+steps:
+  - compound:
+      maxParallel: 1
+      steps:
+        - run:
+            name: one
+            image: alpine
+            parallel: true
+        - run:
+            name: two
+            image: alpine
+            parallel: true
+        - run:
+            name: three
+            image: alpine
+            parallel: true
+`,
+			steps:      []string{"one", "two", "three"},
+			maxRunning: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			workflow := parseTestWorkflow(t, test.workflow)
+
+			script := &fake.Script{Steps: make(map[string]fake.StepScript)}
+			for _, step := range test.steps {
+				script.Steps[step] = fake.StepScript{Duration: "200ms"}
+			}
+
+			coordinator := executeTestWorkflow(t, workflow, script)
+
+			if workflow.Spec.State.Status != v1.WorkflowSucceeded {
+				t.Errorf("Expected workflow to succeed, but it was %v", workflow.Spec.State.Status)
+			}
+
+			// Queued steps start in the order they appear in the workflow
+			if runs := coordinator.Runs(); !reflect.DeepEqual(runs, test.steps) {
+				t.Errorf("Expected steps to run in order %v, but they ran in order %v", test.steps, runs)
+			}
+
+			if maxRunning := coordinator.MaxRunning(); maxRunning != test.maxRunning {
+				t.Errorf("Expected at most %v steps to run at the same time, but %v did",
+					test.maxRunning, maxRunning)
+			}
+		})
+	}
+}
+
+func TestFailFast(t *testing.T) {
+	tests := []struct {
+		name     string
+		workflow string
+		waits    bool
+	}{
+		{
+			name: "default",
+			workflow: `
+steps:
+  - run:
+      name: slow
+      image: alpine
+      parallel: true
+  - run:
+      name: broken
+      image: alpine
+      parallel: true
+`,
+			waits: false,
+		},
+		{
+			name: "workflow",
+			workflow: `
+failFast: false
+steps:
+  - run:
+      name: slow
+      image: alpine
+      parallel: true
+  - run:
+      name: broken
+      image: alpine
+      parallel: true
+`,
+			waits: true,
+		},
+		{
+			name: "compound overrides workflow",
+			workflow: `
+failFast: true
+steps:
+  - compound:
+      failFast: false
+      steps:
+        - run:
+            name: slow
+            image: alpine
+            parallel: true
+        - run:
+            name: broken
+            image: alpine
+            parallel: true
+`,
+			waits: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			workflow := parseTestWorkflow(t, test.workflow)
+
+			coordinator := executeTestWorkflow(t, workflow, &fake.Script{
+				Steps: map[string]fake.StepScript{
+					"slow":   {Duration: "500ms"},
+					"broken": {Failure: "Broken"},
+				},
+			})
+
+			if workflow.Spec.State.Status != v1.WorkflowFailed {
+				t.Errorf("Expected workflow to fail, but it was %v", workflow.Spec.State.Status)
+			}
+
+			if status := stepStatus(workflow, "broken"); status != v1.StepFailed {
+				t.Errorf("Expected step broken to fail, but it was %v", status)
+			}
+
+			// The workflow finishes as soon as the failure is reported, so step slow only finishes before
+			// the workflow does if the failure waited for it
+			finished := false
+			for _, step := range coordinator.Finished() {
+				if step == "slow" {
+					finished = true
+				}
+			}
+
+			if test.waits && !finished {
+				t.Errorf("Expected the failure to be reported after step slow finished, but it was reported before")
+			} else if !test.waits && finished {
+				t.Errorf("Expected the failure to be reported right away, but it waited for step slow to finish")
+			}
+		})
+	}
+}
@@ -4,10 +4,12 @@ import (
 	executioncontext "github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
 	"github.com/stackfoundation/sandbox/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func logChange(c *v1.Change) {
+func raiseChange(sc *executioncontext.StepContext, c *v1.Change) {
 	log.Debugf("Raised %v event for step %v", c.Type, c.StepSelector)
+	sc.WorkflowContext.Observer.ChangeRaised(sc.WorkflowContext.Workflow, c)
 }
 
 func consumeTransition(sc *executioncontext.StepContext) {
@@ -25,7 +27,7 @@ func imageBuiltTransition(sc *executioncontext.StepContext) {
 	change := handleChangeAndAppend(sc, sc.WorkflowContext.Workflow, sc.NextStepSelector)
 	change.Type = v1.StepImageBuilt
 
-	logChange(change)
+	raiseChange(sc, change)
 }
 
 func initialTransition(sc *executioncontext.StepContext) {
@@ -36,12 +38,71 @@ func initialTransition(sc *executioncontext.StepContext) {
 
 	w.AppendChange(change)
 
-	logChange(change)
+	raiseChange(sc, change)
+}
+
+func finishStep(step *v1.WorkflowStep, status v1.StepStatus) {
+	finished := metav1.Now()
+
+	step.State.Status = status
+	step.State.Finished = &finished
+}
+
+func stepAbortedTransition(sc *executioncontext.StepContext) {
+	w := sc.WorkflowContext.Workflow
+	step := w.Select(sc.StepSelector)
+
+	if step.State.Status != v1.StepFailed {
+		finishStep(step, v1.StepFailed)
+	}
+
+	w.Spec.State.Status = v1.WorkflowFailed
+
+	change := handleChangeAndAppend(sc, w, sc.StepSelector)
+	change.Type = v1.StepAborted
+
+	raiseChange(sc, change)
+
+	sc.WorkflowContext.Cancel()
+}
+
+func stepFailedTransition(sc *executioncontext.StepContext) {
+	step := sc.WorkflowContext.Workflow.Select(sc.StepSelector)
+	finishStep(step, v1.StepFailed)
+}
+
+func stepBypassedTransition(sc *executioncontext.StepContext) {
+	change := handleChangeAndAppend(sc, sc.WorkflowContext.Workflow, sc.NextStepSelector)
+	change.Type = v1.StepBypassed
+
+	raiseChange(sc, change)
+}
+
+func recordPodTimings(step *v1.WorkflowStep, podTimings *v1.StepTimings) {
+	timings := &step.State.Timings
+
+	if podTimings.PodRequested != nil {
+		timings.PodRequested = podTimings.PodRequested
+	}
+
+	if podTimings.PodCreated != nil {
+		timings.PodCreated = podTimings.PodCreated
+	}
+
+	if podTimings.ContainerStarted != nil {
+		timings.ContainerStarted = podTimings.ContainerStarted
+	}
+
+	if podTimings.Ready != nil {
+		timings.Ready = podTimings.Ready
+	}
 }
 
 type stepDoneTransition struct {
+	failed             bool
 	generatedContainer string
 	generatedWorkfow   string
+	timings            v1.StepTimings
 	variables          []v1.VariableSource
 }
 
@@ -51,10 +112,18 @@ func (t *stepDoneTransition) transition(sc *executioncontext.StepContext) {
 	if !step.State.Done {
 		w.Spec.State.Variables.Merge(v1.CollectVariables(t.variables))
 
+		step.State.Variables = t.variables
 		step.State.GeneratedContainer = t.generatedContainer
+		recordPodTimings(step, &t.timings)
 		step.State.Ready = true
 		step.State.Done = true
 
+		if t.failed {
+			finishStep(step, v1.StepFailureIgnored)
+		} else {
+			finishStep(step, v1.StepSucceeded)
+		}
+
 		if step.IsGenerator() {
 			step.State.GeneratedWorkflow = t.generatedWorkfow
 		}
@@ -62,22 +131,27 @@ func (t *stepDoneTransition) transition(sc *executioncontext.StepContext) {
 		change := handleChangeAndAppend(sc, w, sc.StepSelector)
 		change.Type = v1.StepDone
 
-		logChange(change)
+		raiseChange(sc, change)
 	}
 }
 
-func stepReadyTransition(sc *executioncontext.StepContext) {
+type stepReadyTransition struct {
+	timings v1.StepTimings
+}
+
+func (t *stepReadyTransition) transition(sc *executioncontext.StepContext) {
 	w := sc.WorkflowContext.Workflow
 	step := w.Select(sc.StepSelector)
 
 	if !step.State.Ready {
 		change := handleChangeAndAppend(sc, w, sc.StepSelector)
 
+		recordPodTimings(step, &t.timings)
 		step.State.Ready = true
 
 		change.Type = v1.StepReady
 
-		logChange(change)
+		raiseChange(sc, change)
 	}
 }
 
@@ -85,21 +159,34 @@ func stepStartedTransition(sc *executioncontext.StepContext) {
 	change := handleChangeAndAppend(sc, sc.WorkflowContext.Workflow, sc.StepSelector)
 	change.Type = v1.StepStarted
 
-	logChange(change)
+	raiseChange(sc, change)
 }
 
-func workflowWaitDoneTransition(sc *executioncontext.StepContext) {
-	change := handleChangeAndAppend(sc, sc.WorkflowContext.Workflow, sc.StepSelector)
+type workflowWaitDoneTransition struct {
+	failed bool
+}
+
+func (t *workflowWaitDoneTransition) transition(sc *executioncontext.StepContext) {
+	w := sc.WorkflowContext.Workflow
+	step := w.Select(sc.StepSelector)
+
+	if t.failed {
+		finishStep(step, v1.StepFailureIgnored)
+	} else if step.External != nil {
+		finishStep(step, v1.StepSucceeded)
+	}
+
+	change := handleChangeAndAppend(sc, w, sc.StepSelector)
 	change.Type = v1.WorkflowWaitDone
 
-	logChange(change)
+	raiseChange(sc, change)
 }
 
 func workflowWaitTransition(sc *executioncontext.StepContext) {
 	change := handleChangeAndAppend(sc, sc.WorkflowContext.Workflow, sc.StepSelector)
 	change.Type = v1.WorkflowWait
 
-	logChange(change)
+	raiseChange(sc, change)
 }
 
 func (t *pendingTransition) perform() {
@@ -15,7 +15,9 @@ type Controller interface {
 
 type executionController struct {
 	coordinator        coordinator.Coordinator
+	observers          executioncontext.Observers
 	pendingTransitions chan pendingTransition
+	scheduler          *scheduler
 }
 
 type pendingTransition struct {
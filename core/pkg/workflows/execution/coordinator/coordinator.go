@@ -2,56 +2,311 @@ package coordinator
 
 import (
 	"context"
+	"errors"
+	"strings"
+
+	"github.com/docker/engine-api/client"
 
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/docker"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/expansion"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/image"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/kube"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/prune"
+	workflowsv1 "github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
 )
 
-// NewCoordinator Create a new coordinator which uses the specified context
-func NewCoordinator() (Coordinator, error) {
+func createKubeClients(options *Options) (*client.Client, *kubernetes.Clientset, error) {
+	if options.Cluster() {
+		dockerClient, err := docker.CreateEnvDockerClient()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		podsClient, err := kube.CreateClusterKubeClient(options.Kubeconfig, options.Context)
+		return dockerClient, podsClient, err
+	}
+
 	dockerClient, err := docker.CreateDockerClient()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	podsClient, err := kube.CreateKubeClient()
+	return dockerClient, podsClient, err
+}
+
+func newKubeCoordinator(options *Options, workflow *workflowsv1.Workflow) (Coordinator, error) {
+	dockerClient, podsClient, err := createKubeClients(options)
 	if err != nil {
 		return nil, err
 	}
 
+	kube.DeleteOrphanedNamespaces(podsClient, workflow.Spec.State.ID)
+
 	return &executionCoordinator{
 		dockerClient: dockerClient,
+		namespace:    kube.NewNamespace(podsClient, workflow.Spec.State.ProjectRoot, workflow.Spec.State.ID),
 		podsClient:   podsClient,
+		pruner:       createAutoPruner(options, dockerClient),
+		registry:     strings.TrimSuffix(options.Registry, "/"),
+		workflow:     workflow,
 	}, nil
 }
 
+// NewCoordinator Create a new coordinator which runs the steps of the specified workflow as specified by the
+// options
+func NewCoordinator(options *Options, workflow *workflowsv1.Workflow) (Coordinator, error) {
+	switch options.Backend {
+	case "", KubeBackend:
+		return newKubeCoordinator(options, workflow)
+	case DockerBackend:
+		return newDockerCoordinator(options, workflow)
+	}
+
+	return nil, unknownBackend(options.Backend)
+}
+
+// DockerClient Create a client for the Docker daemon which images are built on, as specified by the options
+func DockerClient(options *Options) (*client.Client, error) {
+	switch options.Backend {
+	case "", KubeBackend:
+		if options.Cluster() {
+			return docker.CreateEnvDockerClient()
+		}
+
+		return docker.CreateDockerClient()
+	case DockerBackend:
+		return docker.CreateEnvDockerClient()
+	}
+
+	return nil, unknownBackend(options.Backend)
+}
+
+func vmDiskUsage(images []prune.Artifact) (int64, error) {
+	return docker.VMDiskUsage()
+}
+
+// createAutoPruner Create a pruner for the images generated on the Docker daemon which images are built on, if
+// a prune threshold is specified. The usage of the Sandbox VM's disk is measured when images are built in the
+// Sandbox VM. Otherwise, the disk of the Docker daemon can't be measured, so the threshold is a budget for the
+// total size of generated images instead
+func createAutoPruner(options *Options, dockerClient *client.Client) *prune.AutoPruner {
+	if options.PruneThreshold <= 0 {
+		return nil
+	}
+
+	diskUsage := prune.ImageDiskUsage
+	if options.Backend != DockerBackend && !options.Cluster() {
+		diskUsage = vmDiskUsage
+	}
+
+	return prune.NewAutoPruner(dockerClient, options.PruneThreshold, diskUsage)
+}
+
+// Cluster Are steps run on an existing Kubernetes cluster, instead of the Sandbox VM?
+func (options *Options) Cluster() bool {
+	return len(options.Kubeconfig) > 0 || len(options.Context) > 0
+}
+
+// Validate Check that steps can be run as specified by the options
+func (options *Options) Validate() error {
+	switch options.Backend {
+	case "", KubeBackend:
+		if options.Cluster() && len(options.Registry) == 0 {
+			return errors.New("A registry to push step images to must be specified with --registry, " +
+				"when running on an existing cluster")
+		}
+
+		return nil
+	case DockerBackend:
+		if options.Cluster() {
+			return errors.New("--kubeconfig and --context can only be used with the " + KubeBackend + " backend")
+		}
+
+		return nil
+	}
+
+	return unknownBackend(options.Backend)
+}
+
+func unknownBackend(backend string) error {
+	return errors.New(`Unknown backend "` + backend + `", must be one of: ` + KubeBackend + ", " + DockerBackend)
+}
+
+func (c *executionCoordinator) imageReference(image string) string {
+	if len(c.registry) > 0 {
+		return c.registry + "/sbox-" + image
+	}
+
+	return image
+}
+
 // BuildImage Build an image with the specified options
 func (c *executionCoordinator) BuildImage(context context.Context, image string, options *image.BuildOptions) error {
-	return docker.BuildImage(context, c.dockerClient, image, options)
+	return docker.BuildImage(context, c.dockerClient, c.imageReference(image), options)
+}
+
+// PruneImages Prune the oldest generated images if disk usage is above the prune threshold, keeping the
+// specified images
+func (c *executionCoordinator) PruneImages(context context.Context, kept []string) {
+	if c.pruner == nil {
+		return
+	}
+
+	references := make([]string, 0, len(kept))
+	for _, image := range kept {
+		references = append(references, c.imageReference(image))
+	}
+
+	c.pruner.PruneIfNecessary(context, references)
+}
+
+// DistributeImage Make an image which was built available to the nodes which run steps. When running on an
+// existing cluster, the image is pushed to the registry so that the nodes of the cluster can pull it, with the
+// credentials for the registry from the workflow or the Docker config of the user. As pushing an image whose
+// layers are already in the registry is cheap, images found in the cache are pushed again too, in case an
+// earlier push failed
+func (c *executionCoordinator) DistributeImage(context context.Context, image string) error {
+	if len(c.registry) == 0 {
+		return nil
+	}
+
+	reference := c.imageReference(image)
+
+	registries, err := expansion.ExpandRegistries(c.workflow.Spec.Registries, c.workflow.Spec.State.Variables)
+	if err != nil {
+		return err
+	}
+
+	registryAuth, err := docker.RegistryAuth(reference, registries)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Pushing image %v", reference)
+	return docker.PushImage(context, c.dockerClient, reference, registryAuth)
+}
+
+// TagImage Tag the specified image with another reference. Step images are looked up under the reference
+// they were built with
+func (c *executionCoordinator) TagImage(context context.Context, image string, reference string) error {
+	source := image
+	if strings.HasPrefix(image, "step:") {
+		source = c.imageReference(image)
+	}
+
+	return docker.TagImage(context, c.dockerClient, source, reference)
+}
+
+// PushImage Push the image with the specified reference to the registry it is named after
+func (c *executionCoordinator) PushImage(context context.Context, reference string, registryAuth string) error {
+	return docker.PushImage(context, c.dockerClient, reference, registryAuth)
+}
+
+// ImageBuilt Has an image with the specified name already been built on the Docker daemon which images are
+// built on?
+func (c *executionCoordinator) ImageBuilt(context context.Context, image string) (bool, error) {
+	return docker.ImageExists(context, c.dockerClient, c.imageReference(image))
+}
+
+// ImageID Get the ID of the specified image, or an empty ID if it isn't available to build from
+func (c *executionCoordinator) ImageID(context context.Context, image string) (string, error) {
+	return docker.ImageID(context, c.dockerClient, image)
 }
 
 // CommitContainer Commit the current state of the specified container as a new image
 func (c *executionCoordinator) CommitContainer(context context.Context, containerID string, image string) error {
+	if len(c.registry) > 0 {
+		return errors.New("Steps which continue from the container of a previous step cannot be run on an existing cluster")
+	}
+
 	return docker.CommitContainer(context, c.dockerClient, containerID, image)
 }
 
+func podPullPolicy(policy workflowsv1.PullPolicy, cluster bool) v1.PullPolicy {
+	if policy == workflowsv1.PullNever {
+		return v1.PullNever
+	}
+
+	// Step images are built on the node in the Sandbox VM, so there is nowhere to pull them from, while on an
+	// existing cluster they have just been pushed to the registry
+	if cluster {
+		return v1.PullAlways
+	}
+
+	return v1.PullIfNotPresent
+}
+
+func podPullSecret(reference string, registries []workflowsv1.RegistryCredentials) (*workflowsv1.RegistryCredentials, error) {
+	host := docker.RegistryHost(reference)
+
+	authConfig, found, err := docker.AuthConfig(host, registries)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	if len(authConfig.Username) < 1 {
+		log.Debugf("Credentials for %v can't be used by the cluster to pull images, as they are a token", host)
+		return nil, nil
+	}
+
+	return &workflowsv1.RegistryCredentials{
+		Password: authConfig.Password,
+		Server:   host,
+		Username: authConfig.Username,
+	}, nil
+}
+
+func podCreationSpec(context context.Context, spec *RunStepSpec) *kube.PodCreationSpec {
+	return &kube.PodCreationSpec{
+		LogPrefix:        spec.Name,
+		Image:            spec.Image,
+		Command:          spec.Command,
+		Environment:      spec.Environment,
+		Grace:            spec.Grace,
+		Health:           spec.Health,
+		Ports:            spec.Ports,
+		PullPolicy:       podPullPolicy(spec.PullPolicy, false),
+		Readiness:        spec.Readiness,
+		Reattach:         spec.Reattach,
+		StepID:           spec.StepID,
+		Volumes:          spec.Volumes,
+		Context:          context,
+		Cleanup:          spec.Cleanup,
+		Output:           spec.Output,
+		Listener:         spec.PodListener,
+		VariableReceiver: spec.VariableReceiver,
+		WorkflowReceiver: spec.WorkflowReceiver,
+	}
+}
+
+// PlanStep Get the pod and services which would be created to run a step, without creating them
+func PlanStep(spec *RunStepSpec) (*v1.Pod, []*v1.Service) {
+	return kube.PodManifests(podCreationSpec(context.Background(), spec))
+}
+
 func (c *executionCoordinator) RunStep(context context.Context, spec *RunStepSpec) error {
-	return kube.CreateAndRunPod(
-		c.podsClient,
-		&kube.PodCreationSpec{
-			LogPrefix:        spec.Name,
-			Image:            spec.Image,
-			Command:          spec.Command,
-			Environment:      spec.Environment,
-			Health:           spec.Health,
-			Ports:            spec.Ports,
-			Readiness:        spec.Readiness,
-			Volumes:          spec.Volumes,
-			Context:          context,
-			Cleanup:          spec.Cleanup,
-			Listener:         spec.PodListener,
-			VariableReceiver: spec.VariableReceiver,
-			WorkflowReceiver: spec.WorkflowReceiver,
-		})
+	creationSpec := podCreationSpec(context, spec)
+	creationSpec.Image = c.imageReference(spec.Image)
+
+	if len(c.registry) > 0 {
+		creationSpec.PullPolicy = podPullPolicy(spec.PullPolicy, true)
+
+		pullSecret, err := podPullSecret(creationSpec.Image, spec.Registries)
+		if err != nil {
+			return err
+		}
+
+		creationSpec.PullSecret = pullSecret
+	}
+
+	return kube.CreateAndRunPod(c.namespace, creationSpec)
+}
+
+// Close Delete the namespace of the run, along with anything left in it
+func (c *executionCoordinator) Close() {
+	c.namespace.Delete()
 }
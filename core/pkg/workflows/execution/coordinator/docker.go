@@ -0,0 +1,104 @@
+package coordinator
+
+import (
+	"context"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/docker"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/image"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+func newDockerCoordinator(options *Options, workflow *v1.Workflow) (Coordinator, error) {
+	dockerClient, err := docker.CreateEnvDockerClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &dockerCoordinator{
+		dockerClient: dockerClient,
+		labels:       docker.ArtifactLabels(workflow.Spec.State.ProjectRoot),
+		network:      docker.NewNetwork(dockerClient),
+		pruner:       createAutoPruner(options, dockerClient),
+	}, nil
+}
+
+// BuildImage Build an image with the specified options
+func (c *dockerCoordinator) BuildImage(context context.Context, image string, options *image.BuildOptions) error {
+	return docker.BuildImage(context, c.dockerClient, image, options)
+}
+
+// CommitContainer Commit the current state of the specified container as a new image
+func (c *dockerCoordinator) CommitContainer(context context.Context, containerID string, image string) error {
+	return docker.CommitContainer(context, c.dockerClient, containerID, image)
+}
+
+// DistributeImage Nothing to do, as steps run on the Docker daemon which images are built on
+func (c *dockerCoordinator) DistributeImage(context context.Context, image string) error {
+	return nil
+}
+
+// PruneImages Prune the oldest generated images if disk usage is above the prune threshold, keeping the
+// specified images
+func (c *dockerCoordinator) PruneImages(context context.Context, kept []string) {
+	if c.pruner != nil {
+		c.pruner.PruneIfNecessary(context, kept)
+	}
+}
+
+// ImageBuilt Has an image with the specified name already been built?
+func (c *dockerCoordinator) ImageBuilt(context context.Context, image string) (bool, error) {
+	return docker.ImageExists(context, c.dockerClient, image)
+}
+
+// ImageID Get the ID of the specified image, or an empty ID if it isn't available to build from
+func (c *dockerCoordinator) ImageID(context context.Context, image string) (string, error) {
+	return docker.ImageID(context, c.dockerClient, image)
+}
+
+// TagImage Tag the specified image with another reference
+func (c *dockerCoordinator) TagImage(context context.Context, image string, reference string) error {
+	return docker.TagImage(context, c.dockerClient, image, reference)
+}
+
+// PushImage Push the image with the specified reference to the registry it is named after
+func (c *dockerCoordinator) PushImage(context context.Context, reference string, registryAuth string) error {
+	return docker.PushImage(context, c.dockerClient, reference, registryAuth)
+}
+
+func containerCreationSpec(context context.Context, spec *RunStepSpec, network *docker.Network) *docker.ContainerCreationSpec {
+	creationSpec := &docker.ContainerCreationSpec{
+		LogPrefix:        spec.Name,
+		Image:            spec.Image,
+		Command:          spec.Command,
+		Environment:      spec.Environment,
+		Grace:            spec.Grace,
+		Health:           spec.Health,
+		Network:          network,
+		Ports:            spec.Ports,
+		Readiness:        spec.Readiness,
+		Volumes:          spec.Volumes,
+		Context:          context,
+		Cleanup:          spec.Cleanup,
+		Output:           spec.Output,
+		VariableReceiver: spec.VariableReceiver,
+		WorkflowReceiver: spec.WorkflowReceiver,
+	}
+
+	if spec.PodListener != nil {
+		creationSpec.Listener = spec.PodListener
+	}
+
+	return creationSpec
+}
+
+// RunStep Run a step as a plain container
+func (c *dockerCoordinator) RunStep(context context.Context, spec *RunStepSpec) error {
+	creationSpec := containerCreationSpec(context, spec, c.network)
+	creationSpec.Labels = c.labels
+
+	return docker.CreateAndRunContainer(c.dockerClient, creationSpec)
+}
+
+// Close Nothing to release, as the network of the run is removed once its last container is removed
+func (c *dockerCoordinator) Close() {
+}
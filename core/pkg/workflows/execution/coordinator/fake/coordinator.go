@@ -0,0 +1,319 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/image"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/processors"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+// NewCoordinator Create a fake coordinator which simulates steps according to the specified script. Steps
+// which are not in the script are built and run successfully, without any output
+func NewCoordinator(script *Script) *Coordinator {
+	if script == nil {
+		script = &Script{}
+	}
+
+	return &Coordinator{
+		images:       make(map[string]string),
+		pushAttempts: make(map[string]int),
+		script:       script,
+	}
+}
+
+func (c *Coordinator) stepScript(stepName string) StepScript {
+	if script, ok := c.script.Steps[stepName]; ok {
+		return script
+	}
+
+	return c.script.Steps[strings.TrimPrefix(stepName, "Step ")]
+}
+
+func writeBuildMessage(output io.Writer, message *jsonmessage.JSONMessage) {
+	if output != nil {
+		content, err := json.Marshal(message)
+		if err == nil {
+			output.Write(append(content, '\n'))
+		}
+	}
+}
+
+// BuildImage Simulate building an image, writing the scripted build output
+func (c *Coordinator) BuildImage(context context.Context, image string, options *image.BuildOptions) error {
+	c.lock.Lock()
+	c.builds = append(c.builds, options.StepName)
+	c.lock.Unlock()
+
+	script := c.stepScript(options.StepName)
+	for _, line := range script.BuildOutput {
+		log.Infof("%v", line)
+		writeBuildMessage(options.Output, &jsonmessage.JSONMessage{Stream: line + "\n"})
+	}
+
+	if len(script.BuildFailure) > 0 {
+		writeBuildMessage(options.Output, &jsonmessage.JSONMessage{
+			Error:        &jsonmessage.JSONError{Message: script.BuildFailure},
+			ErrorMessage: script.BuildFailure,
+		})
+
+		return errors.New(script.BuildFailure)
+	}
+
+	c.lock.Lock()
+	c.images[image] = options.StepName
+	c.lock.Unlock()
+
+	return nil
+}
+
+// CommitContainer Simulate committing a container as an image
+func (c *Coordinator) CommitContainer(context context.Context, containerID string, image string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.commits = append(c.commits, containerID)
+	return nil
+}
+
+// DistributeImage Simulate pushing the image of a step to a registry, which fails as many times as scripted
+// for the step the image was built for
+func (c *Coordinator) DistributeImage(context context.Context, image string) error {
+	c.lock.Lock()
+	stepName := c.images[image]
+	c.pushAttempts[stepName]++
+	attempt := c.pushAttempts[stepName]
+	c.lock.Unlock()
+
+	if attempt <= c.stepScript(stepName).PushFailures {
+		return errors.New("Unable to push image of step " + stepName)
+	}
+
+	c.lock.Lock()
+	c.distributions = append(c.distributions, stepName)
+	c.lock.Unlock()
+
+	return nil
+}
+
+// ImageBuilt Has an image with the specified name been built by this coordinator?
+func (c *Coordinator) ImageBuilt(context context.Context, image string) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, built := c.images[image]
+	return built, nil
+}
+
+// ImageID Get a fake ID for the specified image, which is the same for every image with the same name
+func (c *Coordinator) ImageID(context context.Context, image string) (string, error) {
+	return "sha256:" + image, nil
+}
+
+// PruneImages Nothing to prune, as no images are actually built
+func (c *Coordinator) PruneImages(context context.Context, kept []string) {
+}
+
+// TagImage Simulate tagging an image with another reference
+func (c *Coordinator) TagImage(context context.Context, image string, reference string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.tags = append(c.tags, reference)
+	return nil
+}
+
+// PushImage Simulate pushing an image to a registry
+func (c *Coordinator) PushImage(context context.Context, reference string, registryAuth string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.pushes = append(c.pushes, reference)
+	return nil
+}
+
+func printOutput(spec *coordinator.RunStepSpec, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+
+	stream := ioutil.NopCloser(strings.NewReader(strings.Join(lines, "\n") + "\n"))
+	stream = processors.ProcessOutput(stream, &processors.OutputOptions{
+		LogPrefix:        spec.Name,
+		Output:           spec.Output,
+		VariableReceiver: spec.VariableReceiver,
+		WorkflowReceiver: spec.WorkflowReceiver,
+	})
+	defer stream.Close()
+
+	_, _ = io.Copy(log.Output(), stream)
+}
+
+func (c *Coordinator) simulateStep(spec *coordinator.RunStepSpec, containerID string) {
+	script := c.stepScript(spec.Name)
+	listener := spec.PodListener
+
+	if listener != nil {
+		listener.Container(containerID)
+		listener.Started()
+	}
+
+	printOutput(spec, script.Output)
+
+	if len(script.Duration) > 0 {
+		duration, err := time.ParseDuration(script.Duration)
+		if err != nil {
+			log.Errorf("Invalid duration %v for step %v: %v", script.Duration, spec.Name, err.Error())
+		}
+
+		time.Sleep(duration)
+	}
+
+	if len(script.Failure) > 0 {
+		c.finish(spec.Name)
+		if listener != nil {
+			listener.Done(true, script.Failure)
+		}
+
+		return
+	}
+
+	if listener == nil {
+		c.finish(spec.Name)
+		return
+	}
+
+	if !script.NotReady {
+		listener.Ready()
+	}
+
+	if !spec.Service {
+		c.finish(spec.Name)
+		listener.Done(false, "")
+	}
+}
+
+func (c *Coordinator) finish(stepName string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.running--
+	c.finished = append(c.finished, stepName)
+}
+
+// RunStep Simulate running a step, writing the scripted output and finishing as scripted. Services keep
+// running until the workflow finishes
+func (c *Coordinator) RunStep(context context.Context, spec *coordinator.RunStepSpec) error {
+	c.lock.Lock()
+	c.runs = append(c.runs, spec.Name)
+	if spec.Reattach {
+		c.reattaches = append(c.reattaches, spec.Name)
+	}
+	c.running++
+	if c.running > c.maxRunning {
+		c.maxRunning = c.running
+	}
+	containerID := "fake-" + strings.Replace(strings.ToLower(spec.Name), " ", "-", -1)
+	c.lock.Unlock()
+
+	if spec.Cleanup != nil {
+		spec.Cleanup.Add(1)
+		go func() {
+			<-context.Done()
+			spec.Cleanup.Done()
+		}()
+	}
+
+	if spec.PodListener != nil {
+		spec.PodListener.Created()
+	}
+
+	go c.simulateStep(spec, containerID)
+	return nil
+}
+
+// Close Nothing to release, as nothing is really run
+func (c *Coordinator) Close() {
+}
+
+// Builds Get the names of the steps whose images were built, in the order they were built
+func (c *Coordinator) Builds() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]string(nil), c.builds...)
+}
+
+// Commits Get the IDs of the containers which were committed, in the order they were committed
+func (c *Coordinator) Commits() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]string(nil), c.commits...)
+}
+
+// Distributions Get the names of the steps whose images were made available to run, in the order they were
+// made available
+func (c *Coordinator) Distributions() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]string(nil), c.distributions...)
+}
+
+// Finished Get the names of the steps which finished running, in the order they finished. Services, which keep
+// running until the workflow finishes, are not included
+func (c *Coordinator) Finished() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]string(nil), c.finished...)
+}
+
+// MaxRunning Get the largest number of steps which were running at the same time
+func (c *Coordinator) MaxRunning() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.maxRunning
+}
+
+// Reattaches Get the names of the steps which were reattached to, in the order they were reattached to
+func (c *Coordinator) Reattaches() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]string(nil), c.reattaches...)
+}
+
+// Runs Get the names of the steps which were run, in the order they were run
+func (c *Coordinator) Runs() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]string(nil), c.runs...)
+}
+
+// Tags Get the references which images were tagged with, in the order they were tagged
+func (c *Coordinator) Tags() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]string(nil), c.tags...)
+}
+
+// Pushes Get the references of the images which were pushed, in the order they were pushed
+func (c *Coordinator) Pushes() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]string(nil), c.pushes...)
+}
@@ -0,0 +1,134 @@
+package fake
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/errors"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/events"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+// ReadScript Read a script for the fake coordinator from a YAML file
+func ReadScript(path string) (*Script, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var script Script
+	err = yaml.Unmarshal(content, &script)
+	if err != nil {
+		return nil, err
+	}
+
+	return &script, nil
+}
+
+func matches(expected, actual string) bool {
+	return len(expected) == 0 || expected == actual
+}
+
+func (expected *ExpectedEvent) matches(event *events.Event) bool {
+	return matches(expected.Type, string(event.Type)) &&
+		matches(expected.Workflow, event.Workflow) &&
+		matches(expected.Step, event.Step) &&
+		matches(expected.Change, string(event.Change)) &&
+		matches(expected.Status, event.Status) &&
+		matches(expected.Name, event.Name) &&
+		matches(expected.Value, event.Value)
+}
+
+func (expected *ExpectedEvent) String() string {
+	description := expected.Type
+	if len(description) == 0 {
+		description = "event"
+	}
+
+	for _, field := range [][2]string{
+		{"workflow", expected.Workflow},
+		{"step", expected.Step},
+		{"change", expected.Change},
+		{"status", expected.Status},
+		{"name", expected.Name},
+		{"value", expected.Value},
+	} {
+		if len(field[1]) > 0 {
+			description += " " + field[0] + "=" + field[1]
+		}
+	}
+
+	return description
+}
+
+func checkEvents(expected []ExpectedEvent, emitted []events.Event, composite *errors.CompositeError) {
+	next := 0
+	for i := range emitted {
+		if next < len(expected) && expected[next].matches(&emitted[i]) {
+			next++
+		}
+	}
+
+	if next < len(expected) {
+		composite.Append(fmt.Errorf("Expected %v, after %v matching events, but it was not emitted",
+			expected[next].String(), next))
+	}
+}
+
+func stepStatuses(workflow *v1.Workflow) map[string]string {
+	statuses := make(map[string]string)
+
+	selector := workflow.IncrementStepSelector([]int{})
+	for len(selector) > 0 {
+		step := workflow.Select(selector)
+		status := string(step.State.Status)
+
+		statuses[workflow.StepPath(selector)] = status
+		statuses[step.StepName(selector)] = status
+
+		selector = workflow.IncrementStepSelector(selector)
+	}
+
+	return statuses
+}
+
+// Check Check the outcome of a workflow run against the expectations, returning an error which describes every
+// expectation that was not met
+func (e *Expectations) Check(workflow *v1.Workflow, emitted []events.Event) error {
+	composite := errors.NewCompositeError()
+
+	status := string(workflow.Spec.State.Status)
+	if !matches(e.Status, status) {
+		composite.Append(fmt.Errorf("Expected workflow %v to be %v, but it was %v", workflow.Name, e.Status, status))
+	}
+
+	statuses := stepStatuses(workflow)
+	for step, expected := range e.Steps {
+		actual, ok := statuses[step]
+		if !ok {
+			composite.Append(fmt.Errorf("Expected step %v to be %v, but there is no such step", step, expected))
+		} else if expected != actual {
+			composite.Append(fmt.Errorf("Expected step %v to be %v, but it was %v", step, expected, actual))
+		}
+	}
+
+	var variables map[string]string
+	if workflow.Spec.State.Variables != nil {
+		variables = workflow.Spec.State.Variables.Map()
+	}
+
+	for name, expected := range e.Variables {
+		actual, ok := variables[name]
+		if !ok {
+			composite.Append(fmt.Errorf("Expected variable %v to be \"%v\", but it was not set", name, expected))
+		} else if expected != actual {
+			composite.Append(fmt.Errorf("Expected variable %v to be \"%v\", but it was \"%v\"", name, expected, actual))
+		}
+	}
+
+	checkEvents(e.Events, emitted, composite)
+
+	return composite.OrNilIfEmpty()
+}
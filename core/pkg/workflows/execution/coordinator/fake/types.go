@@ -0,0 +1,62 @@
+package fake
+
+import (
+	"sync"
+)
+
+// Script Scripted behaviour of the steps of a workflow run by the fake coordinator, and the expected outcome of
+// the run
+type Script struct {
+	Expect *Expectations         `json:"expect"`
+	Steps  map[string]StepScript `json:"steps"`
+}
+
+// StepScript Scripted behaviour of a step, identified by its name, or by its number if it has no name
+type StepScript struct {
+	BuildFailure string   `json:"buildFailure"`
+	BuildOutput  []string `json:"buildOutput"`
+	Duration     string   `json:"duration"`
+	Failure      string   `json:"failure"`
+	NotReady     bool     `json:"notReady"`
+	Output       []string `json:"output"`
+	PushFailures int      `json:"pushFailures"`
+}
+
+// Expectations Expected outcome of a workflow run
+type Expectations struct {
+	Events    []ExpectedEvent   `json:"events"`
+	Status    string            `json:"status"`
+	Steps     map[string]string `json:"steps"`
+	Variables map[string]string `json:"variables"`
+}
+
+// ExpectedEvent An event expected to be emitted during a workflow run. Fields which are left empty match any
+// value
+type ExpectedEvent struct {
+	Change   string `json:"change"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Step     string `json:"step"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	Workflow string `json:"workflow"`
+}
+
+// Coordinator A coordinator which simulates building images and running steps according to a script, without
+// Docker or Kubernetes
+type Coordinator struct {
+	builds        []string
+	commits       []string
+	distributions []string
+	finished      []string
+	images        map[string]string
+	lock          sync.Mutex
+	maxRunning    int
+	pushAttempts  map[string]int
+	pushes        []string
+	reattaches    []string
+	running       int
+	runs          []string
+	script        *Script
+	tags          []string
+}
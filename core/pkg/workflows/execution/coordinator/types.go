@@ -2,26 +2,62 @@ package coordinator
 
 import (
 	"context"
+	"io"
 	"sync"
 
 	"github.com/docker/engine-api/client"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/docker"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/image"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/kube"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/properties"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/prune"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+// KubeBackend Backend which runs steps as pods in the Sandbox Kubernetes cluster
+const KubeBackend = "kube"
+
+// DockerBackend Backend which runs steps as plain containers on the Docker daemon configured in the environment
+const DockerBackend = "docker"
+
+// Options Options which control where steps are built and run
+type Options struct {
+	Backend        string
+	Context        string
+	Kubeconfig     string
+	PruneThreshold int64
+	Registry       string
+}
+
 // Coordinator Coordinates with the various clients needed during workflow execution
 type Coordinator interface {
 	BuildImage(context context.Context, image string, options *image.BuildOptions) error
 	CommitContainer(context context.Context, containerID string, image string) error
+	DistributeImage(context context.Context, image string) error
+	ImageBuilt(context context.Context, image string) (bool, error)
+	ImageID(context context.Context, image string) (string, error)
+	PruneImages(context context.Context, kept []string)
+	PushImage(context context.Context, reference string, registryAuth string) error
 	RunStep(context context.Context, spec *RunStepSpec) error
+	TagImage(context context.Context, image string, reference string) error
+	Close()
 }
 
 type executionCoordinator struct {
 	dockerClient *client.Client
+	namespace    *kube.Namespace
 	podsClient   *kubernetes.Clientset
+	pruner       *prune.AutoPruner
+	registry     string
+	workflow     *v1.Workflow
+}
+
+type dockerCoordinator struct {
+	dockerClient *client.Client
+	labels       map[string]string
+	network      *docker.Network
+	pruner       *prune.AutoPruner
 }
 
 // RunStepSpec Spec for a step to run
@@ -29,12 +65,19 @@ type RunStepSpec struct {
 	Cleanup          *sync.WaitGroup
 	Command          []string
 	Environment      *properties.Properties
+	Grace            string
 	Health           *v1.HealthCheck
 	Image            string
 	Name             string
+	Output           io.Writer
 	PodListener      kube.PodListener
 	Ports            []v1.Port
+	PullPolicy       v1.PullPolicy
 	Readiness        *v1.HealthCheck
+	Reattach         bool
+	Registries       []v1.RegistryCredentials
+	Service          bool
+	StepID           string
 	VariableReceiver func(string, string)
 	Volumes          []v1.Volume
 	WorkflowReceiver func(string)
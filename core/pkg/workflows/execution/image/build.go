@@ -1,14 +1,16 @@
 package image
 
 import (
-	"fmt"
+	"io/ioutil"
 	"strings"
 
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/docker"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/image"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
 	"github.com/stackfoundation/sandbox/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type buildError struct {
@@ -19,47 +21,109 @@ func (err *buildError) Error() string {
 	return err.message
 }
 
+func createContextOptionsForStep(workflowSpec *v1.WorkflowSpec, step *v1.WorkflowStep) *image.BuildOptions {
+	if step.HasDockerfile() {
+		return &image.BuildOptions{
+			ContextDirectory: workflowSpec.State.ProjectRoot,
+			DockerfilePath:   step.Dockerfile(),
+		}
+	}
+
+	options := &image.BuildOptions{
+		ContextDirectory: workflowSpec.State.ProjectRoot,
+	}
+
+	source := step.Source()
+	if source != nil {
+		options.Dockerignore = source.Dockerignore
+		options.SourceIncludes = source.Include
+		options.SourceExcludes = source.Exclude
+	}
+
+	return options
+}
+
+// StepContextExcludes Get the patterns of the files which are excluded from the context of the image
+// build for a step
+func StepContextExcludes(workflowSpec *v1.WorkflowSpec, step *v1.WorkflowStep) ([]string, error) {
+	return image.ContextExcludes(createContextOptionsForStep(workflowSpec, step))
+}
+
+// stepImageLabels Get the labels to put on the image of a step. The labels which mark the image as generated
+// by Sandbox can't be overridden by the step
+func stepImageLabels(workflowSpec *v1.WorkflowSpec, step *v1.WorkflowStep) map[string]string {
+	labels := v1.CollectVariables(step.Labels()).Map()
+	for label, value := range docker.ArtifactLabels(workflowSpec.State.ProjectRoot) {
+		labels[label] = value
+	}
+
+	return labels
+}
+
 func createBuildOptionsForStepImage(workflowSpec *v1.WorkflowSpec, step *v1.WorkflowStep) *image.BuildOptions {
 	scriptContent := step.Script()
 
 	if step.HasScript() {
-		if step.Cached() {
-			step.State.GeneratedScript = v1.GenerateCachedScriptName(scriptContent)
-		} else {
-			step.State.GeneratedScript = v1.GenerateScriptName()
-		}
+		step.State.GeneratedScript = v1.GenerateCachedScriptName(scriptContent)
 	}
 
+	options := createContextOptionsForStep(workflowSpec, step)
+	options.BuildArgs = v1.CollectVariables(step.BuildArgs()).Map()
+	options.Labels = stepImageLabels(workflowSpec, step)
+	options.NoCache = step.NoCache()
+	options.Pull = step.PullPolicy() == v1.PullAlways
+	options.Target = step.Target()
+
 	if step.HasDockerfile() {
-		return &image.BuildOptions{
-			ContextDirectory: workflowSpec.State.ProjectRoot,
-			DockerfilePath:   step.Dockerfile(),
-		}
+		return options
 	}
 
-	dockerfileContent := buildDockerfile(step)
+	options.ScriptName = step.State.GeneratedScript
+	options.DockerfileContent = strings.NewReader(buildDockerfile(step))
+	options.ScriptContent = strings.NewReader(scriptContent)
 
-	var dockerignore string
-	var sourceInclude []string
-	var sourceExclude []string
+	return options
+}
 
-	source := step.Source()
-	if source != nil {
-		dockerignore = source.Dockerignore
-		sourceInclude = source.Include
-		sourceExclude = source.Exclude
+func recordBuildTimings(step *v1.WorkflowStep, statistics *image.ContextStatistics) {
+	timings := &step.State.Timings
+	buildFinished := metav1.Now()
+
+	if !statistics.Started.IsZero() {
+		contextStarted := metav1.NewTime(statistics.Started)
+		timings.ContextStarted = &contextStarted
+	}
+
+	if !statistics.Finished.IsZero() {
+		contextFinished := metav1.NewTime(statistics.Finished)
+		timings.ContextFinished = &contextFinished
 	}
 
-	return &image.BuildOptions{
-		ContextDirectory:  workflowSpec.State.ProjectRoot,
-		DockerfilePath:    "",
-		Dockerignore:      dockerignore,
-		SourceIncludes:    sourceInclude,
-		SourceExcludes:    sourceExclude,
-		ScriptName:        step.State.GeneratedScript,
-		DockerfileContent: strings.NewReader(dockerfileContent),
-		ScriptContent:     strings.NewReader(scriptContent),
+	timings.ContextFiles = statistics.Files
+	timings.ContextSize = statistics.Size
+	timings.BuildFinished = &buildFinished
+}
+
+// workflowImages Get the images generated for the steps of a workflow so far, which later steps can still
+// build on or run
+func workflowImages(workflow *v1.Workflow) []string {
+	var images []string
+
+	selector := workflow.IncrementStepSelector([]int{})
+	for len(selector) > 0 {
+		step := workflow.Select(selector)
+		if len(step.State.GeneratedBaseImage) > 0 {
+			images = append(images, step.State.GeneratedBaseImage)
+		}
+
+		if len(step.State.GeneratedImage) > 0 {
+			images = append(images, step.State.GeneratedImage)
+		}
+
+		selector = workflow.IncrementStepSelector(selector)
 	}
+
+	return images
 }
 
 // BuildStepImage Build the image for a step
@@ -69,6 +133,11 @@ func BuildStepImage(coordinator coordinator.Coordinator, sc *context.StepContext
 
 	// collectCherryPicks(coordinator, sc, step)
 
+	if step.State.Reattach {
+		log.Debugf("Reusing image %v of step %v, which is reattached to", step.State.GeneratedImage, stepName)
+		return nil
+	}
+
 	if step.UsesPreviousStep() {
 		err := commitPreviousStepImage(coordinator, sc, step)
 		if err != nil {
@@ -76,16 +145,56 @@ func BuildStepImage(coordinator coordinator.Coordinator, sc *context.StepContext
 		}
 	}
 
+	workflowSpec := &sc.WorkflowContext.Workflow.Spec
+	options := createBuildOptionsForStepImage(workflowSpec, step)
+
+	if step.PullPolicy() == v1.PullNever {
+		err := checkBaseImagesPresent(sc.WorkflowContext.Context, coordinator, step, stepName)
+		if err != nil {
+			return err
+		}
+	}
+
+	authConfigs, err := registryAuthConfigs(workflowSpec, step)
+	if err != nil {
+		return err
+	}
+
+	options.AuthConfigs = authConfigs
+
+	cachedImage, built := findCachedImage(sc.WorkflowContext.Context, coordinator, workflowSpec, step, options)
+	if built {
+		log.Infof("Building image for step %v: cached", stepName)
+		step.State.GeneratedImage = cachedImage
+		recordBuildTimings(step, &image.ContextStatistics{})
+		return coordinator.DistributeImage(sc.WorkflowContext.Context, cachedImage)
+	}
+
 	if step.Cached() {
-		fmt.Println("Building image and running step " + stepName + ":")
+		log.Infof("Building image and running step %v:", stepName)
+	} else {
+		log.Infof("Building image for step %v:", stepName)
+	}
+
+	if len(cachedImage) > 0 {
+		step.State.GeneratedImage = cachedImage
 	} else {
-		fmt.Println("Building image for step " + stepName + ":")
+		step.State.GeneratedImage = v1.GenerateImageName()
 	}
 
-	step.State.GeneratedImage = v1.GenerateImageName()
+	options.Output = sc.WorkflowContext.Observer.StepOutput(sc.WorkflowContext.Workflow, sc.NextStepSelector, context.BuildOutput)
+	options.Statistics = &image.ContextStatistics{}
+	options.StepName = stepName
+
+	coordinator.PruneImages(sc.WorkflowContext.Context, workflowImages(sc.WorkflowContext.Workflow))
 
-	options := createBuildOptionsForStepImage(&sc.WorkflowContext.Workflow.Spec, step)
-	err := coordinator.BuildImage(sc.WorkflowContext.Context, step.State.GeneratedImage, options)
+	err = coordinator.BuildImage(sc.WorkflowContext.Context, step.State.GeneratedImage, options)
+	recordBuildTimings(step, options.Statistics)
+	if err != nil {
+		return err
+	}
+
+	err = coordinator.DistributeImage(sc.WorkflowContext.Context, step.State.GeneratedImage)
 	if err != nil {
 		return err
 	}
@@ -94,3 +203,51 @@ func BuildStepImage(coordinator coordinator.Coordinator, sc *context.StepContext
 
 	return nil
 }
+
+// Plan Plan of how the image for a step would be built
+type Plan struct {
+	Context    *image.ContextSummary `json:"context"`
+	Dockerfile string                `json:"dockerfile"`
+	Script     string                `json:"script"`
+	ScriptName string                `json:"scriptName"`
+}
+
+func readDockerfile(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// PlanStepImage Plan how the image for a step would be built, without building it
+func PlanStepImage(workflowSpec *v1.WorkflowSpec, step *v1.WorkflowStep) (*Plan, error) {
+	if step.UsesPreviousStep() && len(step.State.GeneratedBaseImage) < 1 {
+		step.State.GeneratedBaseImage = "<image of step " + step.Step() + ">"
+	}
+
+	options := createBuildOptionsForStepImage(workflowSpec, step)
+
+	context, err := image.SummarizeContext(options)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{
+		Context:    context,
+		Script:     step.Script(),
+		ScriptName: step.State.GeneratedScript,
+	}
+
+	if step.HasDockerfile() {
+		plan.Dockerfile, err = readDockerfile(step.Dockerfile())
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		plan.Dockerfile = buildDockerfile(step)
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,118 @@
+package image
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"sort"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/image"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+func baseImages(step *v1.WorkflowStep) []string {
+	var images []string
+
+	for _, pick := range step.State.Picks {
+		if len(pick.GeneratedBaseImage) > 0 {
+			images = append(images, pick.GeneratedBaseImage)
+		}
+	}
+
+	if step.UsesPreviousStep() {
+		return append(images, step.State.GeneratedBaseImage)
+	}
+
+	return append(images, step.Image())
+}
+
+func writeKeyPart(hash io.Writer, part string) {
+	io.WriteString(hash, part)
+	io.WriteString(hash, "\x00")
+}
+
+func writeEnvironment(hash io.Writer, workflowSpec *v1.WorkflowSpec, step *v1.WorkflowStep) {
+	environment := v1.CollectVariables(step.Environment())
+	environment.ResolveFrom(workflowSpec.State.Variables)
+
+	writeVariables(hash, environment.Map())
+}
+
+func writeVariables(hash io.Writer, variables map[string]string) {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		writeKeyPart(hash, name+"="+variables[name])
+	}
+}
+
+// stepCacheKey Compute a key which identifies everything that goes into the image of a step: the base
+// images, the generated Dockerfile, the script, the environment of a cached script, the build arguments and
+// labels, and the build context. An empty key is returned when the image can't be identified, such as when
+// a base image hasn't been pulled yet, or when the step asks for a fresh build
+func stepCacheKey(ctx context.Context, coordinator coordinator.Coordinator, workflowSpec *v1.WorkflowSpec,
+	step *v1.WorkflowStep, options *image.BuildOptions) (string, error) {
+	if step.HasDockerfile() || options.NoCache || options.Pull {
+		return "", nil
+	}
+
+	hash := sha256.New()
+
+	for _, baseImage := range baseImages(step) {
+		id, err := coordinator.ImageID(ctx, baseImage)
+		if err != nil || len(id) < 1 {
+			return "", err
+		}
+
+		writeKeyPart(hash, id)
+	}
+
+	writeKeyPart(hash, buildDockerfile(step))
+	writeKeyPart(hash, step.Script())
+
+	if step.Cached() {
+		writeEnvironment(hash, workflowSpec, step)
+	}
+
+	writeKeyPart(hash, "buildArgs")
+	writeVariables(hash, options.BuildArgs)
+	writeKeyPart(hash, "labels")
+	writeVariables(hash, options.Labels)
+
+	contextHash, err := image.HashContext(options)
+	if err != nil {
+		return "", err
+	}
+
+	writeKeyPart(hash, contextHash)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func findCachedImage(ctx context.Context, coordinator coordinator.Coordinator, workflowSpec *v1.WorkflowSpec,
+	step *v1.WorkflowStep, options *image.BuildOptions) (string, bool) {
+	key, err := stepCacheKey(ctx, coordinator, workflowSpec, step, options)
+	if err != nil {
+		log.Debugf("Unable to compute the cache key of the step image: %v", err.Error())
+		return "", false
+	}
+
+	if len(key) < 1 {
+		return "", false
+	}
+
+	cachedImage := v1.GenerateCachedImageName(key)
+	built, err := coordinator.ImageBuilt(ctx, cachedImage)
+	if err != nil {
+		log.Debugf("Unable to check for cached image %v: %v", cachedImage, err.Error())
+	}
+
+	return cachedImage, built
+}
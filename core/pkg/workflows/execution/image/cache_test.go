@@ -0,0 +1,247 @@
+package image
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator/fake"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/image"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+const cachedStep = `
+steps:
+  - run:
+      name: build
+      image: alpine
+      cache: true
+      script: make
+      environment:
+        - name: MODE
+          value: release
+`
+
+func writeContextFile(directory, name, content string) error {
+	return ioutil.WriteFile(filepath.Join(directory, name), []byte(content), 0644)
+}
+
+func createTestContext(t *testing.T) string {
+	directory, err := ioutil.TempDir("", "sbox-cache")
+	if err != nil {
+		t.Fatalf("Unable to create context directory: %v", err)
+	}
+
+	for name, content := range map[string]string{
+		".dockerignore": "*.log\n",
+		"Makefile":      "all:\n\tgo build\n",
+		"main.go":       "package main\n",
+	} {
+		err = writeContextFile(directory, name, content)
+		if err != nil {
+			t.Fatalf("Unable to write context file %v: %v", name, err)
+		}
+	}
+
+	return directory
+}
+
+func testCacheKey(t *testing.T, content string, options *image.BuildOptions) string {
+	workflow, err := v1.ParseWorkflow("/project", "test", []byte(content))
+	if err != nil {
+		t.Fatalf("Unable to parse workflow: %v", err)
+	}
+
+	key, err := stepCacheKey(context.Background(), fake.NewCoordinator(nil), &workflow.Spec,
+		&workflow.Spec.Steps[0], options)
+	if err != nil {
+		t.Fatalf("Unable to compute cache key: %v", err)
+	}
+
+	return key
+}
+
+func TestStepCacheKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		step        string
+		change      func(directory string, options *image.BuildOptions) error
+		invalidates bool
+	}{
+		{
+			name: "nothing changed",
+		},
+		{
+			name: "modification time changed",
+			change: func(directory string, options *image.BuildOptions) error {
+				modified := time.Now().Add(time.Hour)
+				return os.Chtimes(filepath.Join(directory, "main.go"), modified, modified)
+			},
+		},
+		{
+			name: "ignored file added",
+			change: func(directory string, options *image.BuildOptions) error {
+				return writeContextFile(directory, "build.log", "Building")
+			},
+		},
+		{
+			name: "file changed",
+			change: func(directory string, options *image.BuildOptions) error {
+				return writeContextFile(directory, "main.go", "package main\n\nfunc main() {}\n")
+			},
+			invalidates: true,
+		},
+		{
+			name: "file added",
+			change: func(directory string, options *image.BuildOptions) error {
+				return writeContextFile(directory, "README.md", "# Example")
+			},
+			invalidates: true,
+		},
+		{
+			name: "file removed",
+			change: func(directory string, options *image.BuildOptions) error {
+				return os.Remove(filepath.Join(directory, "Makefile"))
+			},
+			invalidates: true,
+		},
+		{
+			name: "build argument changed",
+			change: func(directory string, options *image.BuildOptions) error {
+				options.BuildArgs = map[string]string{"VERSION": "1.2"}
+				return nil
+			},
+			invalidates: true,
+		},
+		{
+			name: "label changed",
+			change: func(directory string, options *image.BuildOptions) error {
+				options.Labels = map[string]string{"version": "1.2"}
+				return nil
+			},
+			invalidates: true,
+		},
+		{
+			name: "base image changed",
+			step: `
+steps:
+  - run:
+      name: build
+      image: alpine:3.6
+      cache: true
+      script: make
+      environment:
+        - name: MODE
+          value: release
+`,
+			invalidates: true,
+		},
+		{
+			name: "script changed",
+			step: `
+steps:
+  - run:
+      name: build
+      image: alpine
+      cache: true
+      script: make all
+      environment:
+        - name: MODE
+          value: release
+`,
+			invalidates: true,
+		},
+		{
+			name: "environment changed",
+			step: `
+steps:
+  - run:
+      name: build
+      image: alpine
+      cache: true
+      script: make
+      environment:
+        - name: MODE
+          value: debug
+`,
+			invalidates: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			directory := createTestContext(t)
+			defer os.RemoveAll(directory)
+
+			options := &image.BuildOptions{ContextDirectory: directory}
+			key := testCacheKey(t, cachedStep, options)
+			if len(key) < 1 {
+				t.Fatalf("Expected a cache key, but it was empty")
+			}
+
+			if test.change != nil {
+				err := test.change(directory, options)
+				if err != nil {
+					t.Fatalf("Unable to make change: %v", err)
+				}
+			}
+
+			step := test.step
+			if len(step) < 1 {
+				step = cachedStep
+			}
+
+			changedKey := testCacheKey(t, step, options)
+			if test.invalidates && changedKey == key {
+				t.Errorf("Expected the cache key to change, but it stayed %v", key)
+			} else if !test.invalidates && changedKey != key {
+				t.Errorf("Expected the cache key to stay %v, but it changed to %v", key, changedKey)
+			}
+		})
+	}
+}
+
+func TestStepCacheKeyDisabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    string
+		options image.BuildOptions
+	}{
+		{
+			name: "dockerfile",
+			step: `
+steps:
+  - run:
+      name: build
+      dockerfile: Dockerfile
+`,
+		},
+		{
+			name:    "no cache",
+			step:    cachedStep,
+			options: image.BuildOptions{NoCache: true},
+		},
+		{
+			name:    "pull",
+			step:    cachedStep,
+			options: image.BuildOptions{Pull: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			directory := createTestContext(t)
+			defer os.RemoveAll(directory)
+
+			options := test.options
+			options.ContextDirectory = directory
+
+			if key := testCacheKey(t, test.step, &options); len(key) > 0 {
+				t.Errorf("Expected no cache key, but it was %v", key)
+			}
+		})
+	}
+}
@@ -1,11 +1,10 @@
 package image
 
 import (
-	"fmt"
-
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
 )
 
 func commitStepImage(coordinator coordinator.Coordinator, sc *context.StepContext, stepName string) (string, error) {
@@ -21,7 +20,7 @@ func commitStepImage(coordinator coordinator.Coordinator, sc *context.StepContex
 
 			generatedImage := v1.GenerateImageName()
 
-			fmt.Printf("Creating image %v from step \"%v\"\n", generatedImage, stepName)
+			log.Infof("Creating image %v from step \"%v\"", generatedImage, stepName)
 			return generatedImage, coordinator.CommitContainer(sc.WorkflowContext.Context, step.State.GeneratedContainer, generatedImage)
 		}
 	}
@@ -2,7 +2,9 @@ package image
 
 import (
 	"bytes"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
 )
@@ -52,6 +54,63 @@ func writeFromInstruction(dockerfile *bytes.Buffer, step *v1.WorkflowStep) {
 	dockerfile.WriteString("\n")
 }
 
+// dockerfileEscaper Escapes the characters which are special within a double-quoted Dockerfile value, so that
+// values are written literally, without any variables in them being substituted
+var dockerfileEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`)
+
+func quoteDockerfileValue(value string) string {
+	return `"` + dockerfileEscaper.Replace(value) + `"`
+}
+
+func writeVariablesInstruction(dockerfile *bytes.Buffer, instruction string, variables []v1.VariableSource) {
+	values := v1.CollectVariables(variables).Map()
+	if len(values) < 1 {
+		return
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	dockerfile.WriteString(instruction)
+	for _, name := range names {
+		dockerfile.WriteString(" ")
+		dockerfile.WriteString(name)
+		dockerfile.WriteString("=")
+		dockerfile.WriteString(quoteDockerfileValue(values[name]))
+	}
+	dockerfile.WriteString("\n")
+}
+
+// writeBuildSetup Write the instructions which customise the image before the source is copied into it, so
+// that the layers they create are reused by builds where only the source has changed
+func writeBuildSetup(dockerfile *bytes.Buffer, build *v1.BuildOptions) {
+	writeVariablesInstruction(dockerfile, "ENV", build.Environment)
+
+	if len(build.Workdir) > 0 {
+		dockerfile.WriteString("WORKDIR ")
+		dockerfile.WriteString(build.Workdir)
+		dockerfile.WriteString("\n")
+	}
+
+	for _, command := range build.Setup {
+		dockerfile.WriteString("RUN ")
+		dockerfile.WriteString(command)
+		dockerfile.WriteString("\n")
+	}
+}
+
+func writeUser(dockerfile *bytes.Buffer, build *v1.BuildOptions) {
+	if len(build.User) > 0 {
+		dockerfile.WriteString("USER ")
+		dockerfile.WriteString(build.User)
+		dockerfile.WriteString("\n")
+	}
+}
+
 func writePorts(dockerfile *bytes.Buffer, step *v1.WorkflowStep) {
 	if step.Service != nil && step.Service.Ports != nil && len(step.Service.Ports) > 0 {
 		dockerfile.WriteString("EXPOSE")
@@ -87,7 +146,7 @@ func writeSourceMount(dockerfile *bytes.Buffer, step *v1.WorkflowStep) {
 
 func writeRunStepScriptInstruction(dockerfile *bytes.Buffer, step *v1.WorkflowStep) {
 	if len(step.State.GeneratedScript) > 0 {
-		dockerfile.WriteString("RUN [\"/bin/sh\", \"")
+		dockerfile.WriteString("RUN [\"/bin/sh\", \"/")
 		dockerfile.WriteString(step.State.GeneratedScript)
 		dockerfile.WriteString("\"]\n")
 	}
@@ -99,7 +158,18 @@ func buildDockerfile(step *v1.WorkflowStep) string {
 	writeCherryPickSources(&dockerfile, step)
 	writeFromInstruction(&dockerfile, step)
 	writeCherryPickCopies(&dockerfile, step)
+
+	build := step.Build()
+	if build != nil {
+		writeBuildSetup(&dockerfile, build)
+	}
+
 	writeSourceMount(&dockerfile, step)
+
+	if build != nil {
+		writeUser(&dockerfile, build)
+	}
+
 	writePorts(&dockerfile, step)
 
 	if step.Cached() {
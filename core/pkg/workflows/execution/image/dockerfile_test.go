@@ -0,0 +1,130 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+func TestBuildDockerfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		step       string
+		script     string
+		dockerfile string
+	}{
+		{
+			name: "no build options",
+			step: `
+steps:
+  - run:
+      name: test
+      image: alpine
+`,
+			script:     "script-1.sh",
+			dockerfile: "FROM alpine\nCOPY . /app/\nCOPY script-1.sh /script-1.sh\n",
+		},
+		{
+			name: "build options",
+			step: `
+steps:
+  - run:
+      name: test
+      image: alpine
+      build:
+        env:
+          - name: PATH
+            value: /usr/local/bin
+          - name: MODE
+            value: release
+        setup:
+          - apk add --no-cache make
+          - adduser -D app
+        user: app
+        workdir: /app
+`,
+			script: "script-1.sh",
+			dockerfile: "FROM alpine\n" +
+				"ENV MODE=\"release\" PATH=\"/usr/local/bin\"\n" +
+				"WORKDIR /app\n" +
+				"RUN apk add --no-cache make\n" +
+				"RUN adduser -D app\n" +
+				"COPY . /app/\n" +
+				"COPY script-1.sh /script-1.sh\n" +
+				"USER app\n",
+		},
+		{
+			name: "escaped environment",
+			step: `
+steps:
+  - run:
+      name: test
+      image: alpine
+      build:
+        env:
+          - name: GREETING
+            value: 'say "hi" to $USER\n'
+`,
+			dockerfile: "FROM alpine\n" +
+				"ENV GREETING=\"say \\\"hi\\\" to \\$USER\\\\n\"\n" +
+				"COPY . /app/\n",
+		},
+		{
+			name: "service",
+			step: `
+steps:
+  - service:
+      name: web
+      image: nginx
+      source:
+        location: /usr/share/nginx/html
+      build:
+        user: nginx
+      ports:
+        - container: "80"
+        - container: "443"
+`,
+			dockerfile: "FROM nginx\n" +
+				"COPY . /usr/share/nginx/html\n" +
+				"USER nginx\n" +
+				"EXPOSE 80 443\n",
+		},
+		{
+			name: "cached",
+			step: `
+steps:
+  - run:
+      name: test
+      image: alpine
+      cache: true
+      build:
+        user: app
+        workdir: /src
+`,
+			script: "script-1.sh",
+			dockerfile: "FROM alpine\n" +
+				"WORKDIR /src\n" +
+				"COPY . /app/\n" +
+				"COPY script-1.sh /script-1.sh\n" +
+				"USER app\n" +
+				"RUN [\"/bin/sh\", \"/script-1.sh\"]\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			workflow, err := v1.ParseWorkflow("/project", "test", []byte(test.step))
+			if err != nil {
+				t.Fatalf("Unable to parse workflow: %v", err)
+			}
+
+			step := &workflow.Spec.Steps[0]
+			step.State.GeneratedScript = test.script
+
+			dockerfile := buildDockerfile(step)
+			if dockerfile != test.dockerfile {
+				t.Errorf("Expected Dockerfile:\n%v\nbut it was:\n%v", test.dockerfile, dockerfile)
+			}
+		})
+	}
+}
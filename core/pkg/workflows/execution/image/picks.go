@@ -1,11 +1,10 @@
 package image
 
 import (
-	"fmt"
-
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
 )
 
 func collectCherryPicks(coordinator coordinator.Coordinator, sc *context.StepContext, step *v1.WorkflowStep) {
@@ -20,7 +19,7 @@ func collectCherryPicks(coordinator coordinator.Coordinator, sc *context.StepCon
 				if step.Name() != cherryPick.Step {
 					pickSourceImage, err := commitStepImage(coordinator, sc, cherryPick.Step)
 					if err != nil {
-						fmt.Printf("Error while cherry-picking file from step %v: %v\n", cherryPick.Step, err.Error())
+						log.Infof("Error while cherry-picking file from step %v: %v", cherryPick.Step, err.Error())
 					} else {
 						pick = &v1.Pick{
 							GeneratedBaseImage: pickSourceImage,
@@ -0,0 +1,60 @@
+package image
+
+import (
+	"context"
+
+	"github.com/docker/engine-api/types"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/docker"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/expansion"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+func registryHosts(step *v1.WorkflowStep, registries []v1.RegistryCredentials) []string {
+	var hosts []string
+
+	for _, baseImage := range baseImages(step) {
+		if len(baseImage) > 0 {
+			hosts = append(hosts, docker.RegistryHost(baseImage))
+		}
+	}
+
+	// The base images of a Dockerfile aren't known up front, so every registry of the workflow is offered
+	for _, registry := range registries {
+		hosts = append(hosts, registry.Server)
+	}
+
+	return hosts
+}
+
+func registryAuthConfigs(workflowSpec *v1.WorkflowSpec, step *v1.WorkflowStep) (map[string]types.AuthConfig, error) {
+	registries, err := expansion.ExpandRegistries(workflowSpec.Registries, workflowSpec.State.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return docker.AuthConfigs(registryHosts(step, registries), registries)
+}
+
+func checkBaseImagesPresent(ctx context.Context, coordinator coordinator.Coordinator, step *v1.WorkflowStep,
+	stepName string) error {
+	for _, baseImage := range baseImages(step) {
+		if len(baseImage) < 1 {
+			continue
+		}
+
+		id, err := coordinator.ImageID(ctx, baseImage)
+		if err != nil {
+			return err
+		}
+
+		if len(id) < 1 {
+			return &buildError{
+				message: "Base image " + baseImage + " is not present, and the pull policy of step " +
+					stepName + " is never",
+			}
+		}
+	}
+
+	return nil
+}
@@ -36,6 +36,14 @@ func expandStep(workflow *v1.Workflow, step *v1.WorkflowStep, stepSelector []int
 	return nil
 }
 
+func pinImage(workflow *v1.Workflow, step *v1.WorkflowStep, stepSelector []int) {
+	pinned, ok := workflow.Spec.State.Pins[step.Image()]
+	if ok {
+		log.Debugf("Using image %v for step %v, as pinned in the lockfile", pinned, step.StepName(stepSelector))
+		step.SetImage(pinned)
+	}
+}
+
 func shouldIgnoreValidation(workflow *v1.Workflow, step *v1.WorkflowStep, stepSelector []int, err error) error {
 	if step.IgnoreValidation() == nil {
 		if !workflow.Spec.IgnoreValidation {
@@ -57,6 +65,8 @@ func PrepareStepIfNecessary(workflow *v1.Workflow, step *v1.WorkflowStep, stepSe
 			return err
 		}
 
+		pinImage(workflow, step, stepSelector)
+
 		err = validation.ValidateStep(step, stepSelector)
 		if err != nil {
 			err = shouldIgnoreValidation(workflow, step, stepSelector, err)
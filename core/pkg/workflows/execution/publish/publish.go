@@ -0,0 +1,149 @@
+package publish
+
+import (
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/docker"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/expansion"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+type publishError struct {
+	message string
+}
+
+func (err *publishError) Error() string {
+	return err.message
+}
+
+func publishRegistries(sc *context.StepContext, publish *v1.PublishOptions) ([]v1.RegistryCredentials, error) {
+	w := sc.WorkflowContext.Workflow
+
+	registries, err := expansion.ExpandRegistries(w.Spec.Registries, w.Spec.State.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(publish.Username) < 1 {
+		return registries, nil
+	}
+
+	password, err := w.Spec.State.Variables.Expand(publish.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	var explicit []v1.RegistryCredentials
+	for _, tag := range publish.Tags {
+		explicit = append(explicit, v1.RegistryCredentials{
+			Password: password,
+			Server:   docker.RegistryHost(tag),
+			Username: publish.Username,
+		})
+	}
+
+	return append(explicit, registries...), nil
+}
+
+func publishImage(coordinator coordinator.Coordinator, sc *context.StepContext, image string, publish *v1.PublishOptions) error {
+	for _, tag := range publish.Tags {
+		log.Infof("Tagging image of step %v as %v", sc.Step.StepName(sc.StepSelector), tag)
+
+		err := coordinator.TagImage(sc.WorkflowContext.Context, image, tag)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !publish.Pushes() {
+		return nil
+	}
+
+	registries, err := publishRegistries(sc, publish)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range publish.Tags {
+		registryAuth, err := docker.RegistryAuth(tag, registries)
+		if err != nil {
+			return err
+		}
+
+		log.Infof("Pushing image %v", tag)
+
+		err = coordinator.PushImage(sc.WorkflowContext.Context, tag, registryAuth)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func commitContainer(coordinator coordinator.Coordinator, sc *context.StepContext, container string,
+	stepName string) (string, error) {
+	generatedImage := v1.GenerateImageName()
+
+	log.Infof("Creating image %v from step \"%v\"", generatedImage, stepName)
+	return generatedImage, coordinator.CommitContainer(sc.WorkflowContext.Context, container, generatedImage)
+}
+
+func commitPublishedStep(coordinator coordinator.Coordinator, sc *context.StepContext, stepName string) (string, error) {
+	w := sc.WorkflowContext.Workflow
+
+	selector := w.FindStep(stepName)
+	if selector == nil {
+		return "", &publishError{
+			message: "Cannot publish image of step \"" + stepName + "\" because there is no step with that name",
+		}
+	}
+
+	step := w.Select(selector)
+	if !step.State.Prepared || len(step.State.GeneratedContainer) < 1 {
+		return "", &publishError{
+			message: "Cannot publish image of step \"" + stepName + "\" because it has not run yet",
+		}
+	}
+
+	return commitContainer(coordinator, sc, step.State.GeneratedContainer, stepName)
+}
+
+// PublishStepImage Tag the image of the specified run or service step as specified by its publish options,
+// and push it if requested. When the script of the step ran in the specified container rather than as part
+// of the image build, the container is committed, so that the published image contains what the script did
+func PublishStepImage(coordinator coordinator.Coordinator, sc *context.StepContext, container string) error {
+	publish := sc.Step.PublishOptions()
+	if publish == nil || len(sc.Step.State.GeneratedImage) < 1 {
+		return nil
+	}
+
+	image := sc.Step.State.GeneratedImage
+	if !sc.Step.Cached() && len(container) > 0 {
+		var err error
+		image, err = commitContainer(coordinator, sc, container, sc.Step.StepName(sc.StepSelector))
+		if err != nil {
+			return err
+		}
+	}
+
+	return publishImage(coordinator, sc, image, publish)
+}
+
+// PublishStep Run a publish step, which tags an existing image, or an image created from the container of a
+// previous step, and pushes it if requested
+func PublishStep(coordinator coordinator.Coordinator, sc *context.StepContext) error {
+	publish := sc.Step.Publish
+
+	image := publish.Image
+	if len(publish.Step) > 0 {
+		var err error
+		image, err = commitPublishedStep(coordinator, sc, publish.Step)
+		if err != nil {
+			return err
+		}
+	}
+
+	return publishImage(coordinator, sc, image, &publish.PublishOptions)
+}
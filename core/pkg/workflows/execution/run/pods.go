@@ -1,11 +1,12 @@
 package run
 
 import (
-	"fmt"
-
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/expansion"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func (l *podCompletionListener) addVariable(name string, value string) {
@@ -23,8 +24,22 @@ func (l *podCompletionListener) Container(containerID string) {
 	l.generatedContainer = containerID
 }
 
+func now() *metav1.Time {
+	now := metav1.Now()
+	return &now
+}
+
+func (l *podCompletionListener) Created() {
+	l.timings.PodCreated = now()
+}
+
+func (l *podCompletionListener) Started() {
+	l.timings.ContainerStarted = now()
+}
+
 func (l *podCompletionListener) Ready() {
-	l.listener.Ready(l.stepContext)
+	l.timings.Ready = now()
+	l.listener.Ready(l.stepContext, &Result{Timings: l.timings})
 }
 
 func (l *podCompletionListener) Done(failed bool, message string) {
@@ -33,6 +48,7 @@ func (l *podCompletionListener) Done(failed bool, message string) {
 		Message:   message,
 		Workflow:  l.generatedWorkflow,
 		Variables: l.variables,
+		Timings:   l.timings,
 	}
 
 	if failed {
@@ -43,58 +59,85 @@ func (l *podCompletionListener) Done(failed bool, message string) {
 	l.listener.Done(l.stepContext, result)
 }
 
-// RunPodStep Run a pod-based step
-func RunPodStep(c coordinator.Coordinator, sc *context.StepContext, l Listener) error {
-	step := sc.Step
-	stepName := step.StepName(sc.Change.StepSelector)
+func newRunStepSpec(workflow *v1.Workflow, step *v1.WorkflowStep, stepSelector []int) *coordinator.RunStepSpec {
+	stepName := step.StepName(stepSelector)
 
 	var command []string
 	if !step.Cached() && len(step.State.GeneratedScript) > 0 {
-		fmt.Println("Running step " + stepName + ":")
-
 		command = []string{"/bin/sh", "/" + step.State.GeneratedScript}
 	}
 
-	step.SetVolumes(normalizeVolumePaths(sc.WorkflowContext.Workflow.Spec.State.ProjectRoot, step.Volumes()))
-
-	completionListener := &podCompletionListener{
-		listener:    l,
-		stepContext: sc,
-	}
+	step.SetVolumes(normalizeVolumePaths(workflow.Spec.State.ProjectRoot, step.Volumes()))
 
 	environment := v1.CollectVariables(step.Environment())
-	environment.ResolveFrom(sc.WorkflowContext.Workflow.Spec.State.Variables)
+	environment.ResolveFrom(workflow.Spec.State.Variables)
 
 	if len(step.Name()) < 1 {
 		stepName = "Step " + stepName
 	}
 
+	var grace string
 	var ports []v1.Port
 	var health *v1.HealthCheck
 	var readiness *v1.HealthCheck
 
 	if step.Service != nil {
+		grace = step.Service.Grace
 		ports = step.Service.Ports
 		health = step.Service.Health
 		readiness = step.Service.Readiness
 	}
 
-	err := c.RunStep(
-		sc.WorkflowContext.Context,
-		&coordinator.RunStepSpec{
-			Command:          command,
-			Cleanup:          sc.WorkflowContext.Cleanup,
-			Environment:      environment,
-			Health:           health,
-			Image:            step.State.GeneratedImage,
-			Name:             stepName,
-			PodListener:      completionListener,
-			Ports:            ports,
-			Readiness:        readiness,
-			VariableReceiver: completionListener.addVariable,
-			Volumes:          step.Volumes(),
-			WorkflowReceiver: completionListener.addGeneratedWorkflow,
-		})
-
-	return err
+	return &coordinator.RunStepSpec{
+		Command:     command,
+		Environment: environment,
+		Grace:       grace,
+		Health:      health,
+		Image:       step.State.GeneratedImage,
+		Name:        stepName,
+		Ports:       ports,
+		PullPolicy:  step.PullPolicy(),
+		Readiness:   readiness,
+		Reattach:    step.State.Reattach,
+		Service:     step.Service != nil,
+		StepID:      workflow.Spec.State.ID + "/" + workflow.StepPath(stepSelector),
+		Volumes:     step.Volumes(),
+	}
+}
+
+// PlanPodStep Get the spec with which a pod-based step would be run, without running it
+func PlanPodStep(workflow *v1.Workflow, step *v1.WorkflowStep, stepSelector []int) *coordinator.RunStepSpec {
+	return newRunStepSpec(workflow, step, stepSelector)
+}
+
+// RunPodStep Run a pod-based step
+func RunPodStep(c coordinator.Coordinator, sc *context.StepContext, l Listener) error {
+	step := sc.Step
+
+	if !step.Cached() && len(step.State.GeneratedScript) > 0 {
+		log.Infof("Running step %v:", step.StepName(sc.Change.StepSelector))
+	}
+
+	workflow := sc.WorkflowContext.Workflow
+	spec := newRunStepSpec(workflow, step, sc.Change.StepSelector)
+
+	registries, err := expansion.ExpandRegistries(workflow.Spec.Registries, workflow.Spec.State.Variables)
+	if err != nil {
+		return err
+	}
+
+	completionListener := &podCompletionListener{
+		listener:    l,
+		stepContext: sc,
+	}
+	completionListener.timings.PodRequested = now()
+
+	spec.Cleanup = sc.WorkflowContext.Cleanup
+	spec.Output = sc.WorkflowContext.Observer.StepOutput(sc.WorkflowContext.Workflow, sc.StepSelector, context.RunOutput)
+	spec.PodListener = completionListener
+	spec.Registries = registries
+	spec.VariableReceiver = completionListener.addVariable
+	spec.WorkflowReceiver = completionListener.addGeneratedWorkflow
+
+	return c.RunStep(sc.WorkflowContext.Context, spec)
 }
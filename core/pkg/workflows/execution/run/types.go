@@ -11,11 +11,12 @@ type Result struct {
 	Message   string
 	Workflow  string
 	Variables []v1.VariableSource
+	Timings   v1.StepTimings
 }
 
 // Listener Listens to a pod step run
 type Listener interface {
-	Ready(sc *context.StepContext)
+	Ready(sc *context.StepContext, r *Result)
 	Failed(sc *context.StepContext, r *Result)
 	Done(sc *context.StepContext, r *Result)
 }
@@ -25,5 +26,6 @@ type podCompletionListener struct {
 	stepContext        *context.StepContext
 	generatedContainer string
 	generatedWorkflow  string
+	timings            v1.StepTimings
 	variables          []v1.VariableSource
 }
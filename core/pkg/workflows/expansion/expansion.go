@@ -25,6 +25,8 @@ func expandRunStepOptions(run *v1.RunStepOptions, variables *properties.Properti
 	run.Parallel = parallel
 	composite.Append(err)
 
+	composite.Append(expandPublishOptions(run.Publish, variables))
+
 	return composite.OrNilIfEmpty()
 }
 
@@ -38,6 +40,8 @@ func ExpandStep(step *v1.WorkflowStep, variables *properties.Properties) error {
 		return expandExternalStepOptions(step.External, variables)
 	} else if step.Service != nil {
 		return expandServiceStepOptions(step.Service, variables)
+	} else if step.Publish != nil {
+		return expandPublishStepOptions(step.Publish, variables)
 	}
 
 	return nil
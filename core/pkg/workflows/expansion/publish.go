@@ -0,0 +1,49 @@
+package expansion
+
+import (
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/errors"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/properties"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+// expandPublishOptions Replace the variable placeholders in the specified publish options. The password
+// keeps its placeholders, so that secrets aren't stored in the state of the workflow, and is only expanded
+// when the image is pushed
+func expandPublishOptions(publish *v1.PublishOptions, variables *properties.Properties) error {
+	if publish == nil {
+		return nil
+	}
+
+	composite := errors.NewCompositeError()
+
+	push, err := variables.Expand(publish.Push)
+	publish.Push = push
+	composite.Append(err)
+
+	tags, err := expandStringSlice(publish.Tags, variables)
+	publish.Tags = tags
+	composite.Append(err)
+
+	username, err := variables.Expand(publish.Username)
+	publish.Username = username
+	composite.Append(err)
+
+	return composite.OrNilIfEmpty()
+}
+
+func expandPublishStepOptions(publish *v1.PublishStepOptions, variables *properties.Properties) error {
+	composite := errors.NewCompositeError()
+
+	composite.Append(expandStepOptions(&publish.StepOptions, variables))
+	composite.Append(expandPublishOptions(&publish.PublishOptions, variables))
+
+	image, err := variables.Expand(publish.Image)
+	publish.Image = image
+	composite.Append(err)
+
+	step, err := variables.Expand(publish.Step)
+	publish.Step = step
+	composite.Append(err)
+
+	return composite.OrNilIfEmpty()
+}
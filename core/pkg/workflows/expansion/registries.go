@@ -0,0 +1,33 @@
+package expansion
+
+import (
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/errors"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/properties"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+// ExpandRegistries Get a copy of the specified registry credentials with their variable placeholders
+// replaced. The workflow keeps the placeholders, so that secrets aren't stored in its state
+func ExpandRegistries(registries []v1.RegistryCredentials, variables *properties.Properties) ([]v1.RegistryCredentials, error) {
+	expandedRegistries := make([]v1.RegistryCredentials, 0, len(registries))
+	composite := errors.NewCompositeError()
+
+	for _, registry := range registries {
+		server, err := variables.Expand(registry.Server)
+		composite.Append(err)
+
+		username, err := variables.Expand(registry.Username)
+		composite.Append(err)
+
+		password, err := variables.Expand(registry.Password)
+		composite.Append(err)
+
+		expandedRegistries = append(expandedRegistries, v1.RegistryCredentials{
+			Password: password,
+			Server:   server,
+			Username: username,
+		})
+	}
+
+	return expandedRegistries, composite.OrNilIfEmpty()
+}
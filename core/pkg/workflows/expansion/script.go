@@ -35,6 +35,12 @@ func expandScriptStepOptions(scriptOptions *v1.ScriptStepOptions, variables *pro
 
 	composite.Append(expandStepOptions(&scriptOptions.StepOptions, variables))
 
+	composite.Append(expandBuildOptions(&scriptOptions.Build, variables))
+
+	buildArgs, err := expandEnvironment(scriptOptions.BuildArgs, variables)
+	scriptOptions.BuildArgs = buildArgs
+	composite.Append(err)
+
 	dockerfile, err := variables.Expand(scriptOptions.Dockerfile)
 	scriptOptions.Dockerfile = dockerfile
 	composite.Append(err)
@@ -47,6 +53,18 @@ func expandScriptStepOptions(scriptOptions *v1.ScriptStepOptions, variables *pro
 	scriptOptions.Image = image
 	composite.Append(err)
 
+	labels, err := expandEnvironment(scriptOptions.Labels, variables)
+	scriptOptions.Labels = labels
+	composite.Append(err)
+
+	noCache, err := variables.Expand(scriptOptions.NoCache)
+	scriptOptions.NoCache = noCache
+	composite.Append(err)
+
+	pull, err := variables.Expand(scriptOptions.Pull)
+	scriptOptions.Pull = pull
+	composite.Append(err)
+
 	script, err := variables.Expand(scriptOptions.Script)
 	scriptOptions.Script = script
 	composite.Append(err)
@@ -57,6 +75,10 @@ func expandScriptStepOptions(scriptOptions *v1.ScriptStepOptions, variables *pro
 	scriptOptions.Step = previousStep
 	composite.Append(err)
 
+	target, err := variables.Expand(scriptOptions.Target)
+	scriptOptions.Target = target
+	composite.Append(err)
+
 	volumes, err := expandVolumes(scriptOptions.Volumes, variables)
 	scriptOptions.Volumes = volumes
 	composite.Append(err)
@@ -64,6 +86,28 @@ func expandScriptStepOptions(scriptOptions *v1.ScriptStepOptions, variables *pro
 	return composite.OrNilIfEmpty()
 }
 
+func expandBuildOptions(buildOptions *v1.BuildOptions, variables *properties.Properties) error {
+	composite := errors.NewCompositeError()
+
+	environment, err := expandEnvironment(buildOptions.Environment, variables)
+	buildOptions.Environment = environment
+	composite.Append(err)
+
+	setup, err := expandStringSlice(buildOptions.Setup, variables)
+	buildOptions.Setup = setup
+	composite.Append(err)
+
+	user, err := variables.Expand(buildOptions.User)
+	buildOptions.User = user
+	composite.Append(err)
+
+	workdir, err := variables.Expand(buildOptions.Workdir)
+	buildOptions.Workdir = workdir
+	composite.Append(err)
+
+	return composite.OrNilIfEmpty()
+}
+
 func expandSourceOptions(sourceOptions *v1.SourceOptions, variables *properties.Properties) error {
 	composite := errors.NewCompositeError()
 
@@ -55,5 +55,7 @@ func expandServiceStepOptions(service *v1.ServiceStepOptions, variables *propert
 	service.Grace = grace
 	composite.Append(err)
 
+	composite.Append(expandPublishOptions(service.Publish, variables))
+
 	return composite.OrNilIfEmpty()
 }
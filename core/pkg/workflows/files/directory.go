@@ -36,7 +36,7 @@ func getSandboxDirectory() (string, error) {
 	return filepath.Join(path, ".sbox"), nil
 }
 
-func getAlternativeWorkflowsDirectory() (string, error) {
+func readSandboxConfig(name string) (string, error) {
 	sboxDirectory, err := getSandboxDirectory()
 	if err != nil {
 		return "", err
@@ -47,18 +47,22 @@ func getAlternativeWorkflowsDirectory() (string, error) {
 		return "", err
 	}
 
-	alternativeWorkflowsConfigFile := filepath.Join(sboxDirectory, "workflows")
-	alternativeWorkflowsConfigFileExists, err := fileExists(alternativeWorkflowsConfigFile)
-	if err != nil || !alternativeWorkflowsConfigFileExists {
+	configFile := filepath.Join(sboxDirectory, name)
+	configFileExists, err := fileExists(configFile)
+	if err != nil || !configFileExists {
 		return "", err
 	}
 
-	alternativeWorkflowsDirectory, err := ioutil.ReadFile(alternativeWorkflowsConfigFile)
+	config, err := ioutil.ReadFile(configFile)
 	if err != nil {
 		return "", err
 	}
 
-	return strings.TrimSpace(string(alternativeWorkflowsDirectory)), nil
+	return strings.TrimSpace(string(config)), nil
+}
+
+func getAlternativeWorkflowsDirectory() (string, error) {
+	return readSandboxConfig("workflows")
 }
 
 func getWorkflowsDirectory() (string, error) {
@@ -74,3 +78,29 @@ func getWorkflowsDirectory() (string, error) {
 
 	return filepath.Join(path, "workflows"), nil
 }
+
+// WorkflowsDirectory Get the directory containing the workflows of the current project
+func WorkflowsDirectory() (string, error) {
+	return getWorkflowsDirectory()
+}
+
+// ProjectBackend Get the execution backend configured for the current project in .sbox/backend, if any
+func ProjectBackend() (string, error) {
+	backend, err := readSandboxConfig("backend")
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+
+	return backend, err
+}
+
+// PruneThreshold Get the disk usage above which old generated images are pruned before step images are
+// built, configured for the current project in .sbox/prune-threshold, if any
+func PruneThreshold() (string, error) {
+	threshold, err := readSandboxConfig("prune-threshold")
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+
+	return threshold, err
+}
@@ -0,0 +1,68 @@
+package files
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const lockExtension = ".lock"
+
+const lockHeader = "# Generated by sbox lock, re-run it to update the pinned images\n"
+
+type imageLock struct {
+	Images map[string]string `yaml:"images"`
+}
+
+func lockFile(workflowName string) (string, error) {
+	workflowsDirectory, err := getWorkflowsDirectory()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(workflowsDirectory, workflowName+lockExtension), nil
+}
+
+// ReadImageLock Read the image digests pinned for the workflow with the specified name, keyed by the image
+// they were resolved from. No images are pinned when the workflow has no lockfile
+func ReadImageLock(workflowName string) (map[string]string, error) {
+	path, err := lockFile(workflowName)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var lock imageLock
+	err = yaml.Unmarshal(content, &lock)
+	if err != nil {
+		return nil, err
+	}
+
+	return lock.Images, nil
+}
+
+// WriteImageLock Write the lockfile of the workflow with the specified name, pinning the specified image
+// digests, keyed by the image they were resolved from
+func WriteImageLock(workflowName string, images map[string]string) (string, error) {
+	path, err := lockFile(workflowName)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := yaml.Marshal(&imageLock{Images: images})
+	if err != nil {
+		return "", err
+	}
+
+	return path, ioutil.WriteFile(path, append([]byte(lockHeader), content...), 0644)
+}
@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"errors"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+func matchesAny(workflow *v1.Workflow, selector []int, paths []string, matched map[string]bool) bool {
+	found := false
+	for _, path := range paths {
+		if workflow.StepMatches(selector, path) {
+			matched[path] = true
+			found = true
+		}
+	}
+
+	return found
+}
+
+func checkAllMatched(workflow *v1.Workflow, paths []string, matched map[string]bool) error {
+	for _, path := range paths {
+		if !matched[path] {
+			return errors.New(`No step named "` + path + `" in workflow ` + workflow.Name)
+		}
+	}
+
+	return nil
+}
+
+func skipStep(step *v1.WorkflowStep) {
+	step.State.Status = v1.StepSkipped
+	step.State.Prepared = true
+	step.State.Ready = true
+	step.State.Done = true
+}
+
+// Filter Skip the steps of a workflow which aren't selected to run. If only is specified, only the steps
+// matching it run, along with any services that precede them. Steps matching skip never run. Steps can
+// be matched by name, or by path in the case of steps within compound steps
+func Filter(workflow *v1.Workflow, only, skip []string, noServices bool) error {
+	if len(only) == 0 && len(skip) == 0 && !noServices {
+		return nil
+	}
+
+	matched := make(map[string]bool)
+
+	var pendingServices []*v1.WorkflowStep
+	var selected int
+
+	selector := workflow.IncrementStepSelector([]int{})
+	for len(selector) > 0 {
+		step := workflow.Select(selector)
+		isOnly := len(only) == 0 || matchesAny(workflow, selector, only, matched)
+		isSkip := matchesAny(workflow, selector, skip, matched)
+
+		if !step.IsSkipped() {
+			if isSkip {
+				skipStep(step)
+			} else if step.Service != nil {
+				if noServices {
+					skipStep(step)
+				} else if !isOnly {
+					pendingServices = append(pendingServices, step)
+				}
+			} else if isOnly {
+				pendingServices = nil
+				selected++
+			} else {
+				skipStep(step)
+			}
+		}
+
+		selector = workflow.IncrementStepSelector(selector)
+	}
+
+	for _, service := range pendingServices {
+		skipStep(service)
+	}
+
+	err := checkAllMatched(workflow, only, matched)
+	if err != nil {
+		return err
+	}
+
+	err = checkAllMatched(workflow, skip, matched)
+	if err != nil {
+		return err
+	}
+
+	if selected == 0 {
+		return errors.New("No steps of workflow " + workflow.Name + " were selected to run")
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+const testWorkflow = `
+steps:
+  - service:
+      name: db
+      image: postgres
+  - run:
+      name: build
+      image: alpine
+      script: make
+  - compound:
+      name: tests
+      steps:
+        - run:
+            name: unit
+            image: alpine
+            script: make test
+        - run:
+            name: integration
+            image: alpine
+            script: make integration
+  - service:
+      name: cache
+      image: redis
+  - run:
+      name: deploy
+      image: alpine
+      script: make deploy
+`
+
+func runningSteps(workflow *v1.Workflow) []string {
+	var steps []string
+
+	selector := workflow.IncrementStepSelector([]int{})
+	for len(selector) > 0 {
+		step := workflow.Select(selector)
+		if !step.IsSkipped() {
+			steps = append(steps, step.StepName(selector))
+		}
+
+		selector = workflow.IncrementStepSelector(selector)
+	}
+
+	return steps
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		only       []string
+		skip       []string
+		noServices bool
+		steps      []string
+		err        string
+	}{
+		{
+			name:  "everything",
+			steps: []string{"db", "build", "unit", "integration", "cache", "deploy"},
+		},
+		{
+			name:  "only with preceding service",
+			only:  []string{"build"},
+			steps: []string{"db", "build"},
+		},
+		{
+			name:  "only with all preceding services",
+			only:  []string{"deploy"},
+			steps: []string{"db", "cache", "deploy"},
+		},
+		{
+			name:  "only compound step",
+			only:  []string{"tests"},
+			steps: []string{"db", "unit", "integration"},
+		},
+		{
+			name:  "only step within compound step by path",
+			only:  []string{"tests/unit"},
+			steps: []string{"db", "unit"},
+		},
+		{
+			name:  "only several steps",
+			only:  []string{"build", "integration"},
+			steps: []string{"db", "build", "integration"},
+		},
+		{
+			name:  "skip compound step",
+			skip:  []string{"tests"},
+			steps: []string{"db", "build", "cache", "deploy"},
+		},
+		{
+			name:  "skip service",
+			skip:  []string{"db"},
+			steps: []string{"build", "unit", "integration", "cache", "deploy"},
+		},
+		{
+			name:  "skip wins over only",
+			only:  []string{"tests"},
+			skip:  []string{"integration"},
+			steps: []string{"db", "unit"},
+		},
+		{
+			name:       "no services",
+			noServices: true,
+			steps:      []string{"build", "unit", "integration", "deploy"},
+		},
+		{
+			name:       "only without services",
+			only:       []string{"deploy"},
+			noServices: true,
+			steps:      []string{"deploy"},
+		},
+		{
+			name: "only missing step",
+			only: []string{"lint"},
+			err:  `No step named "lint" in workflow test`,
+		},
+		{
+			name: "skip missing step",
+			skip: []string{"lint"},
+			err:  `No step named "lint" in workflow test`,
+		},
+		{
+			name: "only services",
+			only: []string{"db"},
+			err:  "No steps of workflow test were selected to run",
+		},
+		{
+			name: "everything skipped",
+			only: []string{"build"},
+			skip: []string{"build"},
+			err:  "No steps of workflow test were selected to run",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			workflow, err := v1.ParseWorkflow("/project", "test", []byte(testWorkflow))
+			if err != nil {
+				t.Fatalf("Unable to parse workflow: %v", err)
+			}
+
+			err = Filter(workflow, test.only, test.skip, test.noServices)
+			if len(test.err) > 0 {
+				if err == nil || err.Error() != test.err {
+					t.Errorf("Expected error \"%v\", but got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unable to filter workflow: %v", err)
+			}
+
+			if steps := runningSteps(workflow); !reflect.DeepEqual(steps, test.steps) {
+				t.Errorf("Expected steps %v to run, but %v are left to run", test.steps, steps)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package graph
+
+import (
+	"regexp"
+	"strconv"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/files"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+var variableDeclaration = regexp.MustCompile(`\bvar\s+([^\s=]+)\s*=`)
+var variablePlaceholder = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+func edgeType(step *v1.WorkflowStep) EdgeType {
+	if step.Service != nil {
+		return ServiceEdge
+	} else if step.IsAsync() {
+		return ParallelEdge
+	}
+
+	return SequenceEdge
+}
+
+func producedVariables(step *v1.WorkflowStep) []string {
+	var variables []string
+	for _, match := range variableDeclaration.FindAllStringSubmatch(step.Script(), -1) {
+		variables = append(variables, match[1])
+	}
+
+	return variables
+}
+
+func consumedVariables(step *v1.WorkflowStep) []string {
+	definition := *step
+	definition.State = v1.StepState{}
+
+	content, err := yaml.Marshal(&definition)
+	if err != nil {
+		return nil
+	}
+
+	var variables []string
+	for _, match := range variablePlaceholder.FindAllStringSubmatch(string(content), -1) {
+		variables = append(variables, match[1])
+	}
+
+	return variables
+}
+
+func (b *builder) newID(prefix string) string {
+	b.ids++
+	return prefix + strconv.Itoa(b.ids)
+}
+
+func (b *builder) addEdge(from, to string, edgeType EdgeType, label string) {
+	b.edges = append(b.edges, &Edge{
+		From:  from,
+		To:    to,
+		Type:  edgeType,
+		Label: label,
+	})
+}
+
+func (b *builder) addVariableEdges(step *v1.WorkflowStep, node string) {
+	linked := make(map[string]bool)
+	for _, variable := range consumedVariables(step) {
+		producer, ok := b.producers[variable]
+		if ok && producer != node && !linked[variable] {
+			b.addEdge(producer, node, VariableEdge, variable)
+			linked[variable] = true
+		}
+	}
+
+	for _, variable := range producedVariables(step) {
+		b.producers[variable] = node
+	}
+}
+
+func (b *builder) addExternalWorkflow(step *v1.WorkflowStep, node *Node, cluster *Cluster) {
+	name := step.External.Workflow
+	if b.visiting[name] {
+		node.Label += " (recursive)"
+		return
+	}
+
+	workflow, err := files.ReadWorkflow(name)
+	if err != nil {
+		node.Label += " (not found)"
+		return
+	}
+
+	called := &Cluster{
+		ID:    b.newID("cluster"),
+		Label: "workflow " + name,
+	}
+	cluster.Clusters = append(cluster.Clusters, called)
+
+	b.visiting[name] = true
+	b.addSteps(workflow.Spec.Steps, nil, called, "", make(map[string]string))
+	b.visiting[name] = false
+
+	first := firstNode(called)
+	if first != nil {
+		b.addEdge(node.ID, first.ID, CallEdge, "")
+	}
+}
+
+func firstNode(cluster *Cluster) *Node {
+	if len(cluster.Nodes) > 0 {
+		return cluster.Nodes[0]
+	}
+
+	for _, child := range cluster.Clusters {
+		node := firstNode(child)
+		if node != nil {
+			return node
+		}
+	}
+
+	return nil
+}
+
+func (b *builder) addStep(step *v1.WorkflowStep, selector []int, cluster *Cluster, previous string, names map[string]string) *Node {
+	node := &Node{
+		ID:    b.newID("step"),
+		Label: step.StepName(selector),
+		Type:  step.Type(),
+	}
+	cluster.Nodes = append(cluster.Nodes, node)
+
+	if len(previous) > 0 {
+		b.addEdge(previous, node.ID, edgeType(step), "")
+	}
+
+	if step.UsesPreviousStep() {
+		base, ok := names[step.Step()]
+		if ok {
+			b.addEdge(base, node.ID, ImageEdge, "")
+		}
+	} else if step.Publish != nil && len(step.Publish.Step) > 0 {
+		base, ok := names[step.Publish.Step]
+		if ok {
+			b.addEdge(base, node.ID, ImageEdge, "")
+		}
+	}
+
+	b.addVariableEdges(step, node.ID)
+
+	if len(step.Name()) > 0 {
+		names[step.Name()] = node.ID
+	}
+
+	if step.External != nil {
+		b.addExternalWorkflow(step, node, cluster)
+	}
+
+	return node
+}
+
+func (b *builder) addSteps(steps []v1.WorkflowStep, parent []int, cluster *Cluster, previous string, names map[string]string) string {
+	for i := range steps {
+		step := &steps[i]
+		selector := append(append([]int{}, parent...), i)
+
+		if step.Compound != nil {
+			compound := &Cluster{
+				ID:    b.newID("cluster"),
+				Label: step.StepName(selector),
+			}
+			cluster.Clusters = append(cluster.Clusters, compound)
+
+			previous = b.addSteps(step.Compound.Steps, selector, compound, previous, names)
+			continue
+		}
+
+		node := b.addStep(step, selector, cluster, previous, names)
+		if !step.IsAsync() {
+			previous = node.ID
+		}
+	}
+
+	return previous
+}
+
+// Build Build a graph of the steps in a workflow, including the steps of any workflows it calls
+func Build(workflow *v1.Workflow) *Graph {
+	b := &builder{
+		producers: make(map[string]string),
+		visiting:  map[string]bool{workflow.Name: true},
+	}
+
+	root := &Cluster{
+		ID:    "root",
+		Label: workflow.Name,
+	}
+
+	b.addSteps(workflow.Spec.Steps, nil, root, "", make(map[string]string))
+
+	return &Graph{
+		Root:  root,
+		Edges: b.edges,
+	}
+}
@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+var dotShapes = map[string]string{
+	"run":       "box",
+	"service":   "ellipse",
+	"generator": "hexagon",
+	"external":  "folder",
+	"publish":   "cds",
+}
+
+var dotEdgeStyles = map[EdgeType]string{
+	SequenceEdge: "",
+	ParallelEdge: `style=dashed, label="parallel"`,
+	ServiceEdge:  `style=dotted, label="service"`,
+	ImageEdge:    `style=bold, color=blue, label="image"`,
+	CallEdge:     `style=bold, label="calls"`,
+}
+
+func writeDotCluster(writer io.Writer, cluster *Cluster, indent string) {
+	for _, node := range cluster.Nodes {
+		shape, ok := dotShapes[node.Type]
+		if !ok {
+			shape = dotShapes["run"]
+		}
+
+		fmt.Fprintf(writer, "%v%v [label=%v, shape=%v];\n",
+			indent, node.ID, strconv.Quote(node.Label+" ("+node.Type+")"), shape)
+	}
+
+	for _, child := range cluster.Clusters {
+		fmt.Fprintf(writer, "%vsubgraph cluster_%v {\n", indent, child.ID)
+		fmt.Fprintf(writer, "%v  label=%v;\n", indent, strconv.Quote(child.Label))
+		writeDotCluster(writer, child, indent+"  ")
+		fmt.Fprintf(writer, "%v}\n", indent)
+	}
+}
+
+func dotEdgeAttributes(edge *Edge) string {
+	if edge.Type == VariableEdge {
+		return `style=dashed, color=darkgreen, label=` + strconv.Quote(edge.Label)
+	}
+
+	return dotEdgeStyles[edge.Type]
+}
+
+// WriteDot Write the graph in Graphviz DOT format
+func (g *Graph) WriteDot(writer io.Writer) error {
+	fmt.Fprintf(writer, "digraph %v {\n", strconv.Quote(g.Root.Label))
+	fmt.Fprintln(writer, "  compound=true;")
+
+	writeDotCluster(writer, g.Root, "  ")
+
+	for _, edge := range g.Edges {
+		attributes := dotEdgeAttributes(edge)
+		if len(attributes) > 0 {
+			attributes = " [" + attributes + "]"
+		}
+
+		fmt.Fprintf(writer, "  %v -> %v%v;\n", edge.From, edge.To, attributes)
+	}
+
+	_, err := fmt.Fprintln(writer, "}")
+	return err
+}
@@ -0,0 +1,120 @@
+package graph
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+// testWorkflow A workflow with a step of every type. The called workflow doesn't exist, so it isn't expanded
+const testWorkflow = `
+steps:
+  - run:
+      name: build
+      image: alpine
+      script: echo var version=1.2
+  - service:
+      name: db
+      image: postgres
+  - generator:
+      name: generate
+      image: alpine
+      script: ./generate.sh
+      parallel: true
+  - compound:
+      name: checks
+      steps:
+        - run:
+            name: test
+            step: build
+            script: make test
+  - external:
+      name: deploy
+      workflow: deploy
+  - publish:
+      name: release
+      step: build
+      tags:
+        - example/app:${version}
+`
+
+const expectedDot = `digraph "test" {
+  compound=true;
+  step1 [label="build (run)", shape=box];
+  step2 [label="db (service)", shape=ellipse];
+  step3 [label="generate (generator)", shape=hexagon];
+  step6 [label="deploy (not found) (external)", shape=folder];
+  step7 [label="release (publish)", shape=cds];
+  subgraph cluster_cluster4 {
+    label="checks";
+    step5 [label="test (run)", shape=box];
+  }
+  step1 -> step2 [style=dotted, label="service"];
+  step2 -> step3 [style=dashed, label="parallel"];
+  step2 -> step5;
+  step1 -> step5 [style=bold, color=blue, label="image"];
+  step5 -> step6;
+  step6 -> step7;
+  step1 -> step7 [style=bold, color=blue, label="image"];
+  step1 -> step7 [style=dashed, color=darkgreen, label="version"];
+}
+`
+
+const expectedMermaid = `flowchart TD
+  step1["build (run)"]
+  step2(["db (service)"])
+  step3{{"generate (generator)"}}
+  step6[["deploy (not found) (external)"]]
+  step7[/"release (publish)"/]
+  subgraph cluster4 ["checks"]
+    step5["test (run)"]
+  end
+  step1 -.->|service| step2
+  step2 -.->|parallel| step3
+  step2 --> step5
+  step1 ==>|image| step5
+  step5 --> step6
+  step6 --> step7
+  step1 ==>|image| step7
+  step1 -.->|"version"| step7
+`
+
+func TestWriteGraph(t *testing.T) {
+	tests := []struct {
+		name     string
+		write    func(*Graph, io.Writer) error
+		expected string
+	}{
+		{
+			name:     "dot",
+			write:    (*Graph).WriteDot,
+			expected: expectedDot,
+		},
+		{
+			name:     "mermaid",
+			write:    (*Graph).WriteMermaid,
+			expected: expectedMermaid,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			workflow, err := v1.ParseWorkflow("/project", "test", []byte(testWorkflow))
+			if err != nil {
+				t.Fatalf("Unable to parse workflow: %v", err)
+			}
+
+			var output bytes.Buffer
+			err = test.write(Build(workflow), &output)
+			if err != nil {
+				t.Fatalf("Unable to write graph: %v", err)
+			}
+
+			if output.String() != test.expected {
+				t.Errorf("Expected graph:\n%v\nbut it was:\n%v", test.expected, output.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+var mermaidShapes = map[string][2]string{
+	"run":       {`["`, `"]`},
+	"service":   {`(["`, `"])`},
+	"generator": {`{{"`, `"}}`},
+	"external":  {`[["`, `"]]`},
+	"publish":   {`[/"`, `"/]`},
+}
+
+var mermaidArrows = map[EdgeType]string{
+	SequenceEdge: "-->",
+	ParallelEdge: "-.->|parallel|",
+	ServiceEdge:  "-.->|service|",
+	ImageEdge:    "==>|image|",
+	CallEdge:     "==>|calls|",
+}
+
+func writeMermaidCluster(writer io.Writer, cluster *Cluster, indent string) {
+	for _, node := range cluster.Nodes {
+		shape, ok := mermaidShapes[node.Type]
+		if !ok {
+			shape = mermaidShapes["run"]
+		}
+
+		fmt.Fprintf(writer, "%v%v%v%v (%v)%v\n",
+			indent, node.ID, shape[0], escapeMermaid(node.Label), node.Type, shape[1])
+	}
+
+	for _, child := range cluster.Clusters {
+		fmt.Fprintf(writer, "%vsubgraph %v [\"%v\"]\n", indent, child.ID, escapeMermaid(child.Label))
+		writeMermaidCluster(writer, child, indent+"  ")
+		fmt.Fprintf(writer, "%vend\n", indent)
+	}
+}
+
+func escapeMermaid(text string) string {
+	return strings.Replace(text, `"`, "#quot;", -1)
+}
+
+func mermaidArrow(edge *Edge) string {
+	if edge.Type == VariableEdge {
+		return "-.->|\"" + escapeMermaid(edge.Label) + "\"|"
+	}
+
+	return mermaidArrows[edge.Type]
+}
+
+// WriteMermaid Write the graph as a Mermaid flowchart
+func (g *Graph) WriteMermaid(writer io.Writer) error {
+	fmt.Fprintln(writer, "flowchart TD")
+
+	writeMermaidCluster(writer, g.Root, "  ")
+
+	for _, edge := range g.Edges {
+		fmt.Fprintf(writer, "  %v %v %v\n", edge.From, mermaidArrow(edge), edge.To)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package graph
+
+// EdgeType Type of relationship between two steps
+type EdgeType string
+
+// SequenceEdge A step runs after another step completes
+const SequenceEdge EdgeType = "sequence"
+
+// ParallelEdge A step runs in parallel, after another step completes
+const ParallelEdge EdgeType = "parallel"
+
+// ServiceEdge A service step is started after another step completes
+const ServiceEdge EdgeType = "service"
+
+// ImageEdge A step uses the image of another step as its base image
+const ImageEdge EdgeType = "image"
+
+// VariableEdge A step uses a variable produced by another step
+const VariableEdge EdgeType = "variable"
+
+// CallEdge A step calls an external workflow
+const CallEdge EdgeType = "calls"
+
+// Node A step in the graph
+type Node struct {
+	ID    string
+	Label string
+	Type  string
+}
+
+// Edge A relationship between two steps in the graph
+type Edge struct {
+	From  string
+	To    string
+	Type  EdgeType
+	Label string
+}
+
+// Cluster A group of steps, for a compound step or a called workflow
+type Cluster struct {
+	ID       string
+	Label    string
+	Nodes    []*Node
+	Clusters []*Cluster
+}
+
+// Graph A graph of the steps in a workflow, and the relationships between them
+type Graph struct {
+	Root  *Cluster
+	Edges []*Edge
+}
+
+type builder struct {
+	edges     []*Edge
+	ids       int
+	producers map[string]string
+	visiting  map[string]bool
+}
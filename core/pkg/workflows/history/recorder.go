@@ -0,0 +1,165 @@
+package history
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	executioncontext "github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+// NewRecorder Create a recorder which records the run of the specified workflow into the run history
+func NewRecorder(workflow *v1.Workflow) (*Recorder, error) {
+	state := &workflow.Spec.State
+	directory := runDirectory(state.ProjectRoot, state.ID)
+
+	err := os.MkdirAll(filepath.Join(directory, logsDirectory), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recorder{
+		directory: directory,
+		logs:      make(map[string]*os.File),
+		root:      workflow,
+		run: &Run{
+			ID:          state.ID,
+			Workflow:    workflow.Name,
+			ProjectRoot: state.ProjectRoot,
+		},
+	}, nil
+}
+
+func (r *Recorder) findStep(workflow *v1.Workflow, path string) *StepRecord {
+	for i := range r.run.Steps {
+		record := &r.run.Steps[i]
+		if record.Workflow == workflow.Name && record.Path == path {
+			return record
+		}
+	}
+
+	return nil
+}
+
+func (r *Recorder) recordStep(workflow *v1.Workflow, selector []int) *StepRecord {
+	path := workflow.StepPath(selector)
+	record := r.findStep(workflow, path)
+	if record == nil {
+		r.run.Steps = append(r.run.Steps, StepRecord{
+			Workflow: workflow.Name,
+			Path:     path,
+			Selector: selector,
+			Log:      strconv.Itoa(len(r.run.Steps)) + ".log",
+		})
+
+		record = &r.run.Steps[len(r.run.Steps)-1]
+	}
+
+	step := workflow.Select(selector)
+	if step != nil {
+		record.Status = step.State.Status
+		record.Started = step.State.Started
+		record.Finished = step.State.Finished
+	}
+
+	return record
+}
+
+// snapshot Take a copy of the spec of a workflow, which isn't changed by steps which are still running
+func snapshot(workflow *v1.Workflow) (*v1.WorkflowSpec, error) {
+	var spec v1.WorkflowSpec
+
+	content, err := json.Marshal(&workflow.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(content, &spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &spec, nil
+}
+
+func (r *Recorder) save() {
+	spec, err := snapshot(r.root)
+	if err != nil {
+		log.Errorf("Error recording the state of workflow %v in the run history: %v", r.root.Name, err.Error())
+		return
+	}
+
+	r.run.Spec = *spec
+	if r.root.Spec.State.Variables != nil {
+		r.run.Variables = r.root.Spec.State.Variables.Map()
+	}
+
+	err = saveRun(r.directory, r.run)
+	if err != nil {
+		log.Errorf("Error saving run %v to the run history: %v", r.run.ID, err.Error())
+	}
+}
+
+// WorkflowStarted Record the start of a workflow
+func (r *Recorder) WorkflowStarted(workflow *v1.Workflow) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.save()
+}
+
+// ChangeRaised Record a change to a step in a workflow
+func (r *Recorder) ChangeRaised(workflow *v1.Workflow, change *v1.Change) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if len(change.StepSelector) > 0 {
+		r.recordStep(workflow, change.StepSelector)
+	}
+
+	r.save()
+}
+
+// StepOutput Get a writer which records the output of a step into its log
+func (r *Recorder) StepOutput(workflow *v1.Workflow, selector []int, stream executioncontext.OutputStream) io.Writer {
+	if stream != executioncontext.RunOutput {
+		return nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	record := r.recordStep(workflow, selector)
+	logFile, ok := r.logs[record.Log]
+	if !ok {
+		var err error
+		logFile, err = os.Create(filepath.Join(r.directory, logsDirectory, record.Log))
+		if err != nil {
+			log.Errorf("Error recording the output of step %v in the run history: %v", record.Path, err.Error())
+			return nil
+		}
+
+		r.logs[record.Log] = logFile
+	}
+
+	return logFile
+}
+
+// WorkflowFinished Record the end of a workflow, closing all logs if it is the recorded workflow
+func (r *Recorder) WorkflowFinished(workflow *v1.Workflow) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if workflow == r.root {
+		for name, logFile := range r.logs {
+			logFile.Close()
+			delete(r.logs, name)
+		}
+	}
+
+	r.save()
+}
@@ -0,0 +1,195 @@
+package history
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/stackfoundation/sandbox/core/pkg/minikube/constants"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+const runFile = "run.json"
+const logsDirectory = "logs"
+const latestRun = "last"
+const runIDPrefix = "wflow-"
+
+func projectKey(projectRoot string) string {
+	hash := md5.New()
+	hash.Write([]byte(filepath.Clean(projectRoot)))
+	return filepath.Base(projectRoot) + "-" + hex.EncodeToString(hash.Sum(nil))[:8]
+}
+
+func projectDirectory(projectRoot string) string {
+	return constants.MakeMiniPath("runs", projectKey(projectRoot))
+}
+
+func runDirectory(projectRoot, id string) string {
+	return filepath.Join(projectDirectory(projectRoot), id)
+}
+
+func saveRun(directory string, run *Run) error {
+	content, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	temporaryFile := filepath.Join(directory, runFile+".tmp")
+	err = ioutil.WriteFile(temporaryFile, content, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(temporaryFile, filepath.Join(directory, runFile))
+}
+
+func loadRun(directory string) (*Run, error) {
+	content, err := ioutil.ReadFile(filepath.Join(directory, runFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var run Run
+	err = json.Unmarshal(content, &run)
+	if err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+type runsByStart []*Run
+
+func (r runsByStart) Len() int {
+	return len(r)
+}
+
+func (r runsByStart) Swap(i, j int) {
+	r[i], r[j] = r[j], r[i]
+}
+
+func (r runsByStart) Less(i, j int) bool {
+	started := r[i].Spec.State.Started
+	otherStarted := r[j].Spec.State.Started
+	if started == nil || otherStarted == nil {
+		return otherStarted == nil && started != nil
+	}
+
+	return otherStarted.Before(started)
+}
+
+// Directory Get the directory in which the specified run is stored
+func (r *Run) Directory() string {
+	return runDirectory(r.ProjectRoot, r.ID)
+}
+
+// Status Get the status of the run
+func (r *Run) Status() v1.WorkflowStatus {
+	return r.Spec.State.Status
+}
+
+// FindStep Find the record of a step in the run, by its path, name or selector
+func (r *Run) FindStep(step string) *StepRecord {
+	for i := range r.Steps {
+		record := &r.Steps[i]
+		if record.Path == step || filepath.Base(record.Path) == step {
+			return record
+		}
+	}
+
+	return nil
+}
+
+// LogFile Get the path to the log file of the specified step
+func (r *Run) LogFile(step *StepRecord) string {
+	return filepath.Join(r.Directory(), logsDirectory, step.Log)
+}
+
+// Reload Reload the run from its stored location
+func (r *Run) Reload() (*Run, error) {
+	return loadRun(r.Directory())
+}
+
+// List List all recorded runs for the specified project, most recent first
+func List(projectRoot string) ([]*Run, error) {
+	entries, err := ioutil.ReadDir(projectDirectory(projectRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var runs []*Run
+	for _, entry := range entries {
+		if entry.IsDir() {
+			run, err := loadRun(filepath.Join(projectDirectory(projectRoot), entry.Name()))
+			if err == nil {
+				runs = append(runs, run)
+			}
+		}
+	}
+
+	sort.Sort(runsByStart(runs))
+	return runs, nil
+}
+
+// Latest Find the most recent recorded run of the specified workflow in the specified project
+func Latest(projectRoot, workflow string) (*Run, error) {
+	runs, err := List(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, run := range runs {
+		if run.Workflow == workflow {
+			return run, nil
+		}
+	}
+
+	return nil, errors.New("No runs of workflow " + workflow + " have been recorded for this project")
+}
+
+// Find Find a recorded run for the specified project, by its ID, a unique prefix of its ID, or "last"
+func Find(projectRoot, reference string) (*Run, error) {
+	runs, err := List(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(runs) == 0 {
+		return nil, errors.New("No runs have been recorded for this project")
+	}
+
+	if reference == latestRun {
+		return runs[0], nil
+	}
+
+	var found *Run
+	for _, run := range runs {
+		if run.ID == reference {
+			return run, nil
+		}
+
+		if strings.HasPrefix(run.ID, reference) || strings.HasPrefix(run.ID, runIDPrefix+reference) {
+			if found != nil {
+				return nil, errors.New(`More than one run matches "` + reference + `"`)
+			}
+
+			found = run
+		}
+	}
+
+	if found == nil {
+		return nil, errors.New(`No run matches "` + reference + `"`)
+	}
+
+	return found, nil
+}
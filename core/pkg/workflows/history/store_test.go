@@ -0,0 +1,151 @@
+package history
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stackfoundation/sandbox/core/pkg/minikube/constants"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testProject = "/project"
+
+func saveTestRun(t *testing.T, id, workflow string, started *metav1.Time) {
+	run := &Run{ID: id, Workflow: workflow, ProjectRoot: testProject}
+	run.Spec.State.Started = started
+
+	err := os.MkdirAll(run.Directory(), 0755)
+	if err != nil {
+		t.Fatalf("Unable to create run directory: %v", err)
+	}
+
+	err = saveRun(run.Directory(), run)
+	if err != nil {
+		t.Fatalf("Unable to save run: %v", err)
+	}
+}
+
+func startedAt(minutes int) *metav1.Time {
+	started := metav1.NewTime(time.Date(2017, 10, 1, 12, minutes, 0, 0, time.UTC))
+	return &started
+}
+
+// useTestHome Use an empty home directory for the run history, returning a function which removes it and
+// restores the previous home directory
+func useTestHome(t *testing.T) func() {
+	home, err := ioutil.TempDir("", "sbox-history")
+	if err != nil {
+		t.Fatalf("Unable to create home directory: %v", err)
+	}
+
+	previous := os.Getenv(constants.MinikubeHome)
+	os.Setenv(constants.MinikubeHome, home)
+
+	return func() {
+		os.Setenv(constants.MinikubeHome, previous)
+		os.RemoveAll(home)
+	}
+}
+
+func createTestHistory(t *testing.T) func() {
+	restore := useTestHome(t)
+
+	saveTestRun(t, "wflow-abc123", "build", startedAt(1))
+	saveTestRun(t, "wflow-abd456", "deploy", startedAt(2))
+	saveTestRun(t, "wflow-xyz789", "build", startedAt(3))
+	saveTestRun(t, "wflow-pending", "build", nil)
+
+	return restore
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name      string
+		reference string
+		id        string
+		err       string
+	}{
+		{
+			name:      "last",
+			reference: "last",
+			id:        "wflow-xyz789",
+		},
+		{
+			name:      "ID",
+			reference: "wflow-abc123",
+			id:        "wflow-abc123",
+		},
+		{
+			name:      "prefix",
+			reference: "wflow-abd",
+			id:        "wflow-abd456",
+		},
+		{
+			name:      "prefix without run ID prefix",
+			reference: "xyz",
+			id:        "wflow-xyz789",
+		},
+		{
+			name:      "ambiguous prefix",
+			reference: "ab",
+			err:       `More than one run matches "ab"`,
+		},
+		{
+			name:      "no match",
+			reference: "lmn",
+			err:       `No run matches "lmn"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer createTestHistory(t)()
+
+			run, err := Find(testProject, test.reference)
+			if len(test.err) > 0 {
+				if err == nil || err.Error() != test.err {
+					t.Errorf("Expected error \"%v\", but got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unable to find run: %v", err)
+			}
+
+			if run.ID != test.id {
+				t.Errorf("Expected run %v, but it was %v", test.id, run.ID)
+			}
+		})
+	}
+}
+
+func TestFindWithoutRuns(t *testing.T) {
+	defer useTestHome(t)()
+
+	_, err := Find(testProject, "last")
+	if err == nil || err.Error() != "No runs have been recorded for this project" {
+		t.Errorf("Expected error for a project without runs, but got %v", err)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	defer createTestHistory(t)()
+
+	run, err := Latest(testProject, "deploy")
+	if err != nil {
+		t.Fatalf("Unable to find latest run: %v", err)
+	}
+
+	if run.ID != "wflow-abd456" {
+		t.Errorf("Expected run wflow-abd456, but it was %v", run.ID)
+	}
+
+	_, err = Latest(testProject, "lint")
+	if err == nil {
+		t.Errorf("Expected an error for a workflow without runs, but there was none")
+	}
+}
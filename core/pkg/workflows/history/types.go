@@ -0,0 +1,39 @@
+package history
+
+import (
+	"os"
+	"sync"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Run A record of a single workflow run
+type Run struct {
+	ID          string            `json:"id"`
+	Workflow    string            `json:"workflow"`
+	ProjectRoot string            `json:"projectRoot"`
+	Steps       []StepRecord      `json:"steps"`
+	Variables   map[string]string `json:"variables"`
+	Spec        v1.WorkflowSpec   `json:"spec"`
+}
+
+// StepRecord A record of a single step within a workflow run
+type StepRecord struct {
+	Workflow string        `json:"workflow"`
+	Path     string        `json:"path"`
+	Selector []int         `json:"selector"`
+	Log      string        `json:"log"`
+	Status   v1.StepStatus `json:"status"`
+	Started  *metav1.Time  `json:"started"`
+	Finished *metav1.Time  `json:"finished"`
+}
+
+// Recorder Records the progress of a workflow run into the run history
+type Recorder struct {
+	directory string
+	lock      sync.Mutex
+	logs      map[string]*os.File
+	root      *v1.Workflow
+	run       *Run
+}
@@ -6,37 +6,51 @@ package image
 import (
 	"io"
 	"path/filepath"
+
+	"github.com/docker/engine-api/types"
 )
 
 // BuildOptions Options for building an image
 type BuildOptions struct {
+	AuthConfigs       map[string]types.AuthConfig
+	BuildArgs         map[string]string
 	ContextDirectory  string
 	DockerfilePath    string
 	Dockerignore      string
+	Labels            map[string]string
+	NoCache           bool
+	Pull              bool
 	SourceIncludes    []string
 	SourceExcludes    []string
 	ScriptName        string
 	DockerfileContent io.Reader
 	ScriptContent     io.Reader
+	Output            io.Writer
+	Statistics        *ContextStatistics
+	StepName          string
+	Target            string
 }
 
-// BuildImageStream Build the tar stream for the context to send to a docker build
-func BuildImageStream(options *BuildOptions) (io.ReadCloser, string, error) {
-	var err error
-	var excludes []string
-
+// ContextExcludes Get the patterns of the files which are excluded from the context of an image build
+// with the specified options
+func ContextExcludes(options *BuildOptions) ([]string, error) {
 	if len(options.SourceIncludes) > 0 || len(options.SourceExcludes) > 0 {
-		excludes = composeDockerignore(options.SourceIncludes, options.SourceExcludes)
-	} else {
-		dockerignore := options.Dockerignore
-		if len(dockerignore) < 1 {
-			dockerignore = filepath.Join(options.ContextDirectory, ".dockerignore")
-		}
+		return composeDockerignore(options.SourceIncludes, options.SourceExcludes), nil
+	}
 
-		excludes, err = readDockerignore(dockerignore)
-		if err != nil {
-			return nil, "", err
-		}
+	dockerignore := options.Dockerignore
+	if len(dockerignore) < 1 {
+		dockerignore = filepath.Join(options.ContextDirectory, ".dockerignore")
+	}
+
+	return readDockerignore(dockerignore)
+}
+
+// BuildImageStream Build the tar stream for the context to send to a docker build
+func BuildImageStream(options *BuildOptions) (io.ReadCloser, string, error) {
+	excludes, err := ContextExcludes(options)
+	if err != nil {
+		return nil, "", err
 	}
 
 	dockerfileTarEntry := ""
@@ -68,5 +82,13 @@ func BuildImageStream(options *BuildOptions) (io.ReadCloser, string, error) {
 		}
 	}
 
+	if buildContext != nil && options.DockerfileContent == nil && len(options.Target) > 0 {
+		buildContext = replaceDockerfileWithTarget(buildContext, dockerfileTarEntry, options.Target)
+	}
+
+	if buildContext != nil && options.Statistics != nil {
+		buildContext = collectStatistics(buildContext, options.Statistics)
+	}
+
 	return buildContext, dockerfileTarEntry, err
 }
@@ -0,0 +1,67 @@
+package image
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+func hashContextFile(hash io.Writer, contextRoot string, file string) error {
+	path := filepath.Join(contextRoot, filepath.FromSlash(file))
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+
+	io.WriteString(hash, file)
+	io.WriteString(hash, "\x00")
+	io.WriteString(hash, info.Mode().String())
+	io.WriteString(hash, "\x00")
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+
+		io.WriteString(hash, target)
+		return nil
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	content, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	_, err = io.Copy(hash, content)
+	return err
+}
+
+// HashContext Compute a hash of the names, modes and contents of the files which would be sent as the
+// context of an image build with the specified options. Modification times are ignored, so the hash
+// only changes when the files themselves do
+func HashContext(options *BuildOptions) (string, error) {
+	summary, err := SummarizeContext(options)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	for _, file := range summary.Files {
+		err = hashContextFile(hash, summary.Directory, file)
+		if err != nil {
+			return "", err
+		}
+
+		io.WriteString(hash, "\x00")
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
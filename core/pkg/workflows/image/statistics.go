@@ -0,0 +1,85 @@
+package image
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+)
+
+// ContextStatistics Statistics about the context sent to a docker build
+type ContextStatistics struct {
+	Files    int
+	Size     int64
+	Started  time.Time
+	Finished time.Time
+}
+
+type statisticsReader struct {
+	counted    chan bool
+	finished   sync.Once
+	reader     io.ReadCloser
+	statistics *ContextStatistics
+	writer     *io.PipeWriter
+}
+
+func countFiles(reader io.Reader, statistics *ContextStatistics, counted chan bool) {
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			break
+		}
+
+		if header.Typeflag == tar.TypeReg || header.Typeflag == tar.TypeRegA {
+			statistics.Files++
+		}
+	}
+
+	io.Copy(ioutil.Discard, reader)
+	close(counted)
+}
+
+func collectStatistics(buildContext io.ReadCloser, statistics *ContextStatistics) io.ReadCloser {
+	pipeReader, pipeWriter := io.Pipe()
+	counted := make(chan bool)
+
+	statistics.Started = time.Now()
+	go countFiles(pipeReader, statistics, counted)
+
+	return &statisticsReader{
+		counted:    counted,
+		reader:     buildContext,
+		statistics: statistics,
+		writer:     pipeWriter,
+	}
+}
+
+func (r *statisticsReader) finish() {
+	r.finished.Do(func() {
+		r.statistics.Finished = time.Now()
+		r.writer.Close()
+	})
+}
+
+func (r *statisticsReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.statistics.Size += int64(n)
+		r.writer.Write(p[:n])
+	}
+
+	if err == io.EOF {
+		r.finish()
+	}
+
+	return n, err
+}
+
+func (r *statisticsReader) Close() error {
+	r.finish()
+	<-r.counted
+
+	return r.reader.Close()
+}
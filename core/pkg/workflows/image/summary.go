@@ -0,0 +1,71 @@
+package image
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ContextSummary Summary of the files sent as the context of an image build
+type ContextSummary struct {
+	Directory string   `json:"directory"`
+	Excludes  []string `json:"excludes"`
+	Files     []string `json:"files"`
+	Size      int64    `json:"size"`
+}
+
+// SummarizeContext Summarize the files which would be sent as the context of an image build with the
+// specified options
+func SummarizeContext(options *BuildOptions) (*ContextSummary, error) {
+	excludes, err := ContextExcludes(options)
+	if err != nil {
+		return nil, err
+	}
+
+	contextRoot, err := getContextRoot(options.ContextDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ContextSummary{
+		Directory: contextRoot,
+		Excludes:  excludes,
+	}
+
+	err = filepath.Walk(contextRoot, func(filePath string, f os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		relFilePath, err := filepath.Rel(contextRoot, filePath)
+		if err != nil {
+			return err
+		}
+
+		skip, err := Matches(relFilePath, excludes)
+		if err != nil {
+			return err
+		}
+
+		if skip {
+			if f.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !f.IsDir() {
+			summary.Files = append(summary.Files, filepath.ToSlash(relFilePath))
+			summary.Size += f.Size()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,138 @@
+package image
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// fromStage Get the name of the stage started by a line of a Dockerfile, and whether the line starts a stage
+func fromStage(line string) (string, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 1 || !strings.EqualFold(fields[0], "FROM") {
+		return "", false
+	}
+
+	arguments := fields[1:]
+	for len(arguments) > 0 && strings.HasPrefix(arguments[0], "--") {
+		arguments = arguments[1:]
+	}
+
+	if len(arguments) >= 3 && strings.EqualFold(arguments[1], "AS") {
+		return arguments[2], true
+	}
+
+	return "", true
+}
+
+// fromImage Get the image which a FROM line of a Dockerfile builds from, skipping any flags
+func fromImage(line string) string {
+	fields := strings.Fields(line)
+	for _, field := range fields[1:] {
+		if !strings.HasPrefix(field, "--") {
+			return field
+		}
+	}
+
+	return ""
+}
+
+// BaseImages Get the distinct images which the stages of a Dockerfile are built from, leaving out stages
+// built from earlier stages, and stages built from scratch
+func BaseImages(dockerfile []byte) ([]string, error) {
+	var images []string
+	var continued bool
+
+	seen := make(map[string]bool)
+	stages := make(map[string]bool)
+
+	scanner := bufio.NewScanner(bytes.NewReader(dockerfile))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if !continued {
+			stage, isFrom := fromStage(line)
+			if isFrom {
+				image := fromImage(line)
+				if len(image) > 0 && !stages[strings.ToLower(image)] && !strings.EqualFold(image, "scratch") &&
+					!seen[image] {
+					images = append(images, image)
+					seen[image] = true
+				}
+
+				if len(stage) > 0 {
+					stages[strings.ToLower(stage)] = true
+				}
+			}
+		}
+
+		trimmed := strings.TrimSpace(line)
+		continued = strings.HasSuffix(trimmed, "\\") && !strings.HasPrefix(trimmed, "#")
+	}
+
+	return images, scanner.Err()
+}
+
+// truncateToTarget Truncate a multi-stage Dockerfile after the specified stage, so that the stage is the last
+// one to be built
+func truncateToTarget(dockerfile []byte, target string) ([]byte, error) {
+	var truncated bytes.Buffer
+	var continued bool
+	var found bool
+
+	scanner := bufio.NewScanner(bytes.NewReader(dockerfile))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if !continued {
+			stage, isFrom := fromStage(line)
+			if isFrom && found {
+				return truncated.Bytes(), nil
+			}
+
+			if isFrom && strings.EqualFold(stage, target) {
+				found = true
+			}
+		}
+
+		trimmed := strings.TrimSpace(line)
+		continued = strings.HasSuffix(trimmed, "\\") && !strings.HasPrefix(trimmed, "#")
+
+		truncated.WriteString(line)
+		truncated.WriteString("\n")
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, errors.New("No stage named " + target + " in the Dockerfile")
+	}
+
+	return truncated.Bytes(), nil
+}
+
+// replaceDockerfileWithTarget Replace the Dockerfile in a build context with one which stops after the
+// target stage. This is how a target stage is built, as the Docker API in use has no notion of targets
+func replaceDockerfileWithTarget(buildContext io.ReadCloser, dockerfileTarEntry string, target string) io.ReadCloser {
+	return replaceFileTarWrapper(buildContext, map[string]TarModifierFunc{
+		dockerfileTarEntry: func(_ string, header *tar.Header, content io.Reader) (*tar.Header, []byte, error) {
+			if header == nil || content == nil {
+				return nil, nil, errors.New("No Dockerfile to build the stage " + target + " of")
+			}
+
+			dockerfile, err := ioutil.ReadAll(content)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			truncated, err := truncateToTarget(dockerfile, target)
+			return header, truncated, err
+		},
+	})
+}
@@ -0,0 +1,152 @@
+package image
+
+import (
+	"reflect"
+	"testing"
+)
+
+const multiStageDockerfile = `FROM golang:1.9 AS build
+RUN go build -o /app
+FROM alpine AS test
+RUN ./test.sh
+FROM alpine
+COPY --from=build /app /app
+`
+
+func TestTruncateToTarget(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerfile string
+		target     string
+		truncated  string
+		err        string
+	}{
+		{
+			name:       "first stage",
+			dockerfile: multiStageDockerfile,
+			target:     "build",
+			truncated:  "FROM golang:1.9 AS build\nRUN go build -o /app\n",
+		},
+		{
+			name:       "middle stage",
+			dockerfile: multiStageDockerfile,
+			target:     "test",
+			truncated:  "FROM golang:1.9 AS build\nRUN go build -o /app\nFROM alpine AS test\nRUN ./test.sh\n",
+		},
+		{
+			name:       "last stage",
+			dockerfile: "FROM golang AS build\nRUN go build\nFROM alpine AS final\nCOPY --from=build /app /app",
+			target:     "final",
+			truncated:  "FROM golang AS build\nRUN go build\nFROM alpine AS final\nCOPY --from=build /app /app\n",
+		},
+		{
+			name:       "case insensitive",
+			dockerfile: "from golang as Build\nRUN go build\nFROM alpine\n",
+			target:     "build",
+			truncated:  "from golang as Build\nRUN go build\n",
+		},
+		{
+			name:       "flags",
+			dockerfile: "FROM --platform=linux/amd64 golang AS build\nRUN go build\nFROM alpine\n",
+			target:     "build",
+			truncated:  "FROM --platform=linux/amd64 golang AS build\nRUN go build\n",
+		},
+		{
+			name:       "line continuation",
+			dockerfile: "FROM golang AS build\nRUN echo \\\n  FROM alpine AS next\nFROM alpine\n",
+			target:     "build",
+			truncated:  "FROM golang AS build\nRUN echo \\\n  FROM alpine AS next\n",
+		},
+		{
+			name:       "comment ending with backslash",
+			dockerfile: "FROM golang AS build\n# Build \\\nFROM alpine\n",
+			target:     "build",
+			truncated:  "FROM golang AS build\n# Build \\\n",
+		},
+		{
+			name:       "missing stage",
+			dockerfile: multiStageDockerfile,
+			target:     "lint",
+			err:        "No stage named lint in the Dockerfile",
+		},
+		{
+			name:       "single stage",
+			dockerfile: "FROM alpine\nRUN ./test.sh\n",
+			target:     "test",
+			err:        "No stage named test in the Dockerfile",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			truncated, err := truncateToTarget([]byte(test.dockerfile), test.target)
+			if len(test.err) > 0 {
+				if err == nil || err.Error() != test.err {
+					t.Errorf("Expected error \"%v\", but got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unable to truncate Dockerfile: %v", err)
+			}
+
+			if string(truncated) != test.truncated {
+				t.Errorf("Expected Dockerfile:\n%v\nbut it was:\n%v", test.truncated, string(truncated))
+			}
+		})
+	}
+}
+
+func TestBaseImages(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerfile string
+		images     []string
+	}{
+		{
+			name:       "single stage",
+			dockerfile: "FROM alpine:3.6\nRUN ./test.sh\n",
+			images:     []string{"alpine:3.6"},
+		},
+		{
+			name:       "stages built from earlier stages",
+			dockerfile: multiStageDockerfile + "FROM Build AS package\n",
+			images:     []string{"golang:1.9", "alpine"},
+		},
+		{
+			name:       "scratch",
+			dockerfile: "FROM golang AS build\nFROM scratch\nCOPY --from=build /app /app\n",
+			images:     []string{"golang"},
+		},
+		{
+			name:       "flags",
+			dockerfile: "FROM --platform=linux/amd64 golang AS build\n",
+			images:     []string{"golang"},
+		},
+		{
+			name:       "build arguments",
+			dockerfile: "ARG VERSION=3.6\nFROM alpine:${VERSION}\n",
+			images:     []string{"alpine:${VERSION}"},
+		},
+		{
+			name:       "line continuation",
+			dockerfile: "FROM alpine\nRUN echo \\\n  FROM golang\n",
+			images:     []string{"alpine"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			images, err := BaseImages([]byte(test.dockerfile))
+			if err != nil {
+				t.Fatalf("Unable to get base images: %v", err)
+			}
+
+			if !reflect.DeepEqual(images, test.images) {
+				t.Errorf("Expected base images %v, but they were %v", test.images, images)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package kube
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	log "github.com/stackfoundation/sandbox/log"
+)
+
+const deletionPollInterval = 500 * time.Millisecond
+const deletionTimeout = 30 * time.Second
+
+var cleanupLock sync.Mutex
+var pendingCleanups = make(map[*podContext]bool)
+
+func gracePeriod(grace string) *int64 {
+	if len(grace) > 0 {
+		seconds, err := strconv.ParseInt(grace, 10, 64)
+		if err == nil && seconds >= 0 {
+			return &seconds
+		}
+	}
+
+	return nil
+}
+
+func logCleanup(context *podContext, message string, arguments ...interface{}) {
+	if atomic.LoadInt32(&context.podFinished) == 0 {
+		log.Infof(message, arguments...)
+	} else {
+		log.Debugf(message, arguments...)
+	}
+}
+
+func deletePod(context *podContext, grace *int64) {
+	err := context.podsClient.Delete(context.pod.Name, &metav1.DeleteOptions{GracePeriodSeconds: grace})
+	if err != nil && !kubeerr.IsNotFound(err) {
+		log.Errorf("Error deleting pod %v: %v", context.pod.Name, err.Error())
+	}
+}
+
+func deleteServices(context *podContext, grace *int64) {
+	for _, service := range context.services {
+		logCleanup(context, "Deleting service %v", service.Name)
+
+		err := context.serviceClient.Delete(service.Name, &metav1.DeleteOptions{GracePeriodSeconds: grace})
+		if err != nil && !kubeerr.IsNotFound(err) {
+			log.Errorf("Error deleting service %v: %v", service.Name, err.Error())
+		}
+	}
+}
+
+func waitForPodDeletion(context *podContext, grace int64) {
+	deadline := time.Now().Add(time.Duration(grace)*time.Second + deletionTimeout)
+
+	for time.Now().Before(deadline) {
+		_, err := context.podsClient.Get(context.pod.Name, metav1.GetOptions{})
+		if kubeerr.IsNotFound(err) {
+			return
+		}
+
+		time.Sleep(deletionPollInterval)
+	}
+
+	log.Errorf("Pod %v did not stop within %v seconds", context.pod.Name, grace)
+}
+
+func cleanupPodIfNecessary(context *podContext) {
+	defer context.creationSpec.Cleanup.Done()
+
+	if context.pod == nil {
+		return
+	}
+
+	cleanupLock.Lock()
+	pendingCleanups[context] = true
+	cleanupLock.Unlock()
+
+	defer func() {
+		cleanupLock.Lock()
+		delete(pendingCleanups, context)
+		cleanupLock.Unlock()
+	}()
+
+	grace := gracePeriod(context.creationSpec.Grace)
+
+	logCleanup(context, "Stopping pod %v for step %v", context.pod.Name, context.creationSpec.LogPrefix)
+	deletePod(context, grace)
+	deleteServices(context, grace)
+
+	if grace != nil && *grace > 0 && atomic.LoadInt32(&context.podFinished) == 0 {
+		log.Infof("Waiting up to %v seconds for pod %v to stop", *grace, context.pod.Name)
+		waitForPodDeletion(context, *grace)
+	}
+}
+
+// ForceCleanup Immediately delete any pods, services and namespaces which are still being cleaned up
+func ForceCleanup() {
+	cleanupLock.Lock()
+	immediately := int64(0)
+	for context := range pendingCleanups {
+		log.Infof("Force deleting pod %v", context.pod.Name)
+		deletePod(context, &immediately)
+		deleteServices(context, &immediately)
+	}
+	cleanupLock.Unlock()
+
+	forceDeleteNamespaces()
+}
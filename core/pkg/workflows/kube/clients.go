@@ -27,9 +27,34 @@ func createRestClientConfig() (*rest.Config, error) {
 	return k8sClientConfig.ClientConfig()
 }
 
+func createClusterRestClientConfig(kubeconfigPath string, context string) (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if len(kubeconfigPath) > 0 {
+		loadingRules.ExplicitPath = kubeconfigPath
+	}
+
+	configOverrides := &clientcmd.ConfigOverrides{CurrentContext: context}
+	k8sClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
+	return k8sClientConfig.ClientConfig()
+}
+
+func restClientConfigFor(kubeconfigPath string, context string) (*rest.Config, error) {
+	if len(kubeconfigPath) > 0 || len(context) > 0 {
+		return createClusterRestClientConfig(kubeconfigPath, context)
+	}
+
+	return createRestClientConfig()
+}
+
 // CreateExtensionsClient Create a K8s extensions client
 func CreateExtensionsClient() (*clientset.Clientset, error) {
-	restClientConfig, err := createRestClientConfig()
+	return CreateExtensionsClientFor("", "")
+}
+
+// CreateExtensionsClientFor Create a K8s extensions client for the cluster of the specified kubeconfig file and
+// context, or for the Sandbox VM if neither is specified
+func CreateExtensionsClientFor(kubeconfigPath string, context string) (*clientset.Clientset, error) {
+	restClientConfig, err := restClientConfigFor(kubeconfigPath, context)
 	if err != nil {
 		return nil, err
 	}
@@ -47,6 +72,17 @@ func CreateKubeClient() (*kubernetes.Clientset, error) {
 	return kubernetes.NewForConfig(restClientConfig)
 }
 
+// CreateClusterKubeClient Create a K8s client for an existing cluster, using the specified kubeconfig file and
+// context. The default kubeconfig file and its current context are used if they are not specified
+func CreateClusterKubeClient(kubeconfigPath string, context string) (*kubernetes.Clientset, error) {
+	restClientConfig, err := createClusterRestClientConfig(kubeconfigPath, context)
+	if err != nil {
+		return nil, err
+	}
+
+	return kubernetes.NewForConfig(restClientConfig)
+}
+
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(workflowsv1.SchemeGroupVersion,
 		&workflowsv1.Workflow{},
@@ -58,7 +94,13 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 
 // CreateWorkflowsClient Create a K8s client for workflow resources
 func CreateWorkflowsClient() (*rest.RESTClient, error) {
-	restClientConfig, err := createRestClientConfig()
+	return CreateWorkflowsClientFor("", "")
+}
+
+// CreateWorkflowsClientFor Create a K8s client for workflow resources in the cluster of the specified kubeconfig
+// file and context, or in the Sandbox VM if neither is specified
+func CreateWorkflowsClientFor(kubeconfigPath string, context string) (*rest.RESTClient, error) {
+	restClientConfig, err := restClientConfigFor(kubeconfigPath, context)
 	if err != nil {
 		return nil, err
 	}
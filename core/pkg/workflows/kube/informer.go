@@ -0,0 +1,161 @@
+package kube
+
+import (
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/tools/cache"
+
+	log "github.com/stackfoundation/sandbox/log"
+)
+
+type podEvent struct {
+	pod     *v1.Pod
+	deleted bool
+}
+
+type podEvents struct {
+	closed bool
+	events chan podEvent
+}
+
+// podInformer A shared informer which watches the pods of a single workflow run, and dispatches their events
+// to whoever is waiting for each pod. The informer re-lists and re-watches the pods whenever its watch expires
+type podInformer struct {
+	informer cache.SharedIndexInformer
+	lock     sync.Mutex
+	pods     map[string]*podEvents
+	started  bool
+	stop     chan struct{}
+}
+
+func newPodInformer(clientSet *kubernetes.Clientset, namespace string, run string) *podInformer {
+	selector := runLabel + "=" + run
+	podsClient := clientSet.Pods(namespace)
+
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = selector
+			return podsClient.List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = selector
+			return podsClient.Watch(options)
+		},
+	}
+
+	i := &podInformer{
+		informer: cache.NewSharedIndexInformer(listWatch, &v1.Pod{}, 0, cache.Indexers{}),
+		pods:     make(map[string]*podEvents),
+		stop:     make(chan struct{}),
+	}
+
+	i.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(object interface{}) {
+			i.dispatch(object, false)
+		},
+		UpdateFunc: func(old, object interface{}) {
+			i.dispatch(object, false)
+		},
+		DeleteFunc: func(object interface{}) {
+			if tombstone, ok := object.(cache.DeletedFinalStateUnknown); ok {
+				object = tombstone.Obj
+			}
+
+			i.dispatch(object, true)
+		},
+	})
+
+	return i
+}
+
+func (p *podEvents) send(event podEvent) {
+	if p.closed {
+		return
+	}
+
+	// Only the latest state of a pod matters, so an event which hasn't been received yet is replaced
+	select {
+	case <-p.events:
+	default:
+	}
+
+	p.events <- event
+}
+
+func (i *podInformer) dispatch(object interface{}, deleted bool) {
+	pod, ok := object.(*v1.Pod)
+	if !ok {
+		return
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	events, ok := i.pods[pod.Name]
+	if ok {
+		events.send(podEvent{pod: pod, deleted: deleted})
+	}
+}
+
+func (i *podInformer) start() {
+	if !i.started {
+		i.started = true
+		go i.informer.Run(i.stop)
+	}
+}
+
+// watch Start receiving the events of the specified pod. The current state of the pod is sent straight
+// away if the informer has already seen it
+func (i *podInformer) watch(pod *v1.Pod) <-chan podEvent {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	events := &podEvents{events: make(chan podEvent, 1)}
+	i.pods[pod.Name] = events
+	i.start()
+
+	cached, exists, err := i.informer.GetStore().Get(pod)
+	if err == nil && exists {
+		if cachedPod, ok := cached.(*v1.Pod); ok {
+			events.send(podEvent{pod: cachedPod})
+		}
+	}
+
+	return events.events
+}
+
+// forget Stop receiving the events of the specified pod
+func (i *podInformer) forget(pod *v1.Pod) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	events, ok := i.pods[pod.Name]
+	if ok {
+		events.closed = true
+		close(events.events)
+		delete(i.pods, pod.Name)
+	}
+}
+
+// shutdown Stop the informer, and stop sending events for any pods still being watched
+func (i *podInformer) shutdown() {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if i.started {
+		log.Debugf("Stopping pod informer")
+		close(i.stop)
+		i.started = false
+	}
+
+	for name, events := range i.pods {
+		events.closed = true
+		close(events.events)
+		delete(i.pods, name)
+	}
+}
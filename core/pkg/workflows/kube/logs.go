@@ -2,9 +2,9 @@ package kube
 
 import (
 	"io"
-	"os"
 
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/processors"
+	"github.com/stackfoundation/sandbox/log"
 
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/pkg/api/v1"
@@ -13,25 +13,19 @@ import (
 type podLogPrinter struct {
 	podsClient       corev1.PodInterface
 	logPrefix        string
+	output           io.Writer
 	stream           io.ReadCloser
 	variableReceiver func(string, string)
 	workflowReceiver func(string)
 }
 
 func (printer *podLogPrinter) addLogProcessors(stream io.ReadCloser) io.ReadCloser {
-	if printer.variableReceiver != nil {
-		stream = processors.NewVariableDetector(stream, printer.variableReceiver)
-	}
-
-	if printer.workflowReceiver != nil {
-		stream = processors.NewWorkflowDetector(stream, printer.workflowReceiver)
-	}
-
-	if len(printer.logPrefix) > 0 {
-		stream = processors.NewPrefixer(stream, "\x1b[30;1m["+printer.logPrefix+"]\x1b[0m ")
-	}
-
-	return stream
+	return processors.ProcessOutput(stream, &processors.OutputOptions{
+		LogPrefix:        printer.logPrefix,
+		Output:           printer.output,
+		VariableReceiver: printer.variableReceiver,
+		WorkflowReceiver: printer.workflowReceiver,
+	})
 }
 
 func (printer *podLogPrinter) openAndPrintPodLogs(pod *v1.Pod, follow bool) (io.ReadCloser, error) {
@@ -46,14 +40,14 @@ func (printer *podLogPrinter) openAndPrintPodLogs(pod *v1.Pod, follow bool) (io.
 
 		if follow {
 			go func() {
-				_, _ = io.Copy(os.Stdout, logStream)
+				_, _ = io.Copy(log.Output(), logStream)
 			}()
 
 			return logStream, nil
 		}
 
 		defer logStream.Close()
-		_, _ = io.Copy(os.Stdout, logStream)
+		_, _ = io.Copy(log.Output(), logStream)
 	}
 
 	return nil, nil
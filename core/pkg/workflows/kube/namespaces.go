@@ -0,0 +1,243 @@
+package kube
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+
+	log "github.com/stackfoundation/sandbox/log"
+)
+
+const runLabel = "sbox-run"
+const projectLabel = "sbox-project"
+const stepLabel = "sbox-step"
+const hostAnnotation = "sbox/host"
+const processAnnotation = "sbox/process"
+const projectAnnotation = "sbox/project"
+
+const serviceAccountPollInterval = 250 * time.Millisecond
+const serviceAccountTimeout = 30 * time.Second
+
+var namespacesLock sync.Mutex
+var activeNamespaces = make(map[*Namespace]bool)
+
+// Namespace A namespace which isolates the pods and services of a single workflow run
+type Namespace struct {
+	clientSet   *kubernetes.Clientset
+	created     bool
+	informer    *podInformer
+	lock        sync.Mutex
+	name        string
+	projectRoot string
+	pullSecrets map[string]bool
+	ready       bool
+	run         string
+}
+
+// NewNamespace Create a namespace for the specified workflow run. The namespace is only created in
+// Kubernetes when the first pod is run in it
+func NewNamespace(clientSet *kubernetes.Clientset, projectRoot string, run string) *Namespace {
+	return &Namespace{
+		clientSet:   clientSet,
+		name:        "sbox-" + run,
+		projectRoot: projectRoot,
+		pullSecrets: make(map[string]bool),
+		run:         run,
+	}
+}
+
+func projectHash(projectRoot string) string {
+	hash := md5.Sum([]byte(projectRoot))
+	return hex.EncodeToString(hash[:])
+}
+
+func namespaceManifest(n *Namespace) *v1.Namespace {
+	host, _ := os.Hostname()
+
+	return &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: n.name,
+			Labels: map[string]string{
+				runLabel:     n.run,
+				projectLabel: projectHash(n.projectRoot),
+			},
+			Annotations: map[string]string{
+				hostAnnotation:    host,
+				processAnnotation: strconv.Itoa(os.Getpid()),
+				projectAnnotation: n.projectRoot,
+			},
+		},
+	}
+}
+
+// waitForServiceAccount Wait for the default service account of a new namespace to be created. Pods are
+// rejected by the service account admission plugin until it is
+func waitForServiceAccount(clientSet *kubernetes.Clientset, namespace string) error {
+	deadline := time.Now().Add(serviceAccountTimeout)
+
+	for {
+		_, err := clientSet.ServiceAccounts(namespace).Get("default", metav1.GetOptions{})
+		if err == nil {
+			return nil
+		}
+
+		if !kubeerr.IsNotFound(err) || !time.Now().Before(deadline) {
+			log.Debugf("Default service account of namespace %v is not available: %v", namespace, err.Error())
+			return errors.New("The default service account of namespace " + namespace + " was not created within " +
+				serviceAccountTimeout.String())
+		}
+
+		time.Sleep(serviceAccountPollInterval)
+	}
+}
+
+// Ensure Create the namespace if it hasn't been created yet, and get its name. The namespace is only
+// ready once its default service account exists, which pods run as
+func (n *Namespace) Ensure() (string, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if !n.created {
+		log.Debugf("Creating namespace %v", n.name)
+		_, err := n.clientSet.Namespaces().Create(namespaceManifest(n))
+		if err != nil && !kubeerr.IsAlreadyExists(err) {
+			return "", err
+		}
+
+		n.created = true
+		n.informer = newPodInformer(n.clientSet, n.name, n.run)
+
+		namespacesLock.Lock()
+		activeNamespaces[n] = true
+		namespacesLock.Unlock()
+	}
+
+	if !n.ready {
+		err := waitForServiceAccount(n.clientSet, n.name)
+		if err != nil {
+			return "", err
+		}
+
+		n.ready = true
+	}
+
+	return n.name, nil
+}
+
+func (n *Namespace) pods() *podInformer {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return n.informer
+}
+
+func deleteNamespace(clientSet *kubernetes.Clientset, name string, grace *int64) {
+	err := clientSet.Namespaces().Delete(name, &metav1.DeleteOptions{GracePeriodSeconds: grace})
+	if err != nil && !kubeerr.IsNotFound(err) {
+		log.Errorf("Error deleting namespace %v: %v", name, err.Error())
+	}
+}
+
+// Delete Delete the namespace, along with anything still in it, if it was created
+func (n *Namespace) Delete() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.created {
+		n.informer.shutdown()
+		n.informer = nil
+
+		log.Debugf("Deleting namespace %v", n.name)
+		deleteNamespace(n.clientSet, n.name, nil)
+		n.created = false
+		n.ready = false
+		n.pullSecrets = make(map[string]bool)
+
+		namespacesLock.Lock()
+		delete(activeNamespaces, n)
+		namespacesLock.Unlock()
+	}
+}
+
+func forceDeleteNamespaces() {
+	namespacesLock.Lock()
+	defer namespacesLock.Unlock()
+
+	immediately := int64(0)
+	for n := range activeNamespaces {
+		log.Infof("Force deleting namespace %v", n.name)
+		deleteNamespace(n.clientSet, n.name, &immediately)
+	}
+}
+
+func isOrphaned(namespace *v1.Namespace, host string) bool {
+	if namespace.Status.Phase == v1.NamespaceTerminating {
+		return false
+	}
+
+	if namespace.Annotations[hostAnnotation] != host {
+		return false
+	}
+
+	process, err := strconv.Atoi(namespace.Annotations[processAnnotation])
+	if err != nil {
+		return false
+	}
+
+	return !isProcessRunning(process)
+}
+
+// adoptNamespace Take over the namespace of a run which is being resumed, so that it's no longer considered to
+// be left behind by the process which ran the run before
+func adoptNamespace(clientSet *kubernetes.Clientset, namespace *v1.Namespace) error {
+	host, _ := os.Hostname()
+
+	if namespace.Annotations == nil {
+		namespace.Annotations = make(map[string]string)
+	}
+
+	namespace.Annotations[hostAnnotation] = host
+	namespace.Annotations[processAnnotation] = strconv.Itoa(os.Getpid())
+
+	_, err := clientSet.Namespaces().Update(namespace)
+	return err
+}
+
+// DeleteOrphanedNamespaces Delete the namespaces left behind by workflow runs on this host which are no longer
+// running, such as runs which crashed. The namespace of the specified run, which is being resumed, is taken over
+// instead, so that the pods still running in it can be reattached to
+func DeleteOrphanedNamespaces(clientSet *kubernetes.Clientset, resumed string) {
+	namespaces, err := clientSet.Namespaces().List(metav1.ListOptions{LabelSelector: runLabel})
+	if err != nil {
+		log.Debugf("Unable to list namespaces left behind by previous runs: %v", err.Error())
+		return
+	}
+
+	host, _ := os.Hostname()
+	for i := range namespaces.Items {
+		namespace := &namespaces.Items[i]
+		if len(resumed) > 0 && namespace.Labels[runLabel] == resumed {
+			if namespace.Status.Phase == v1.NamespaceTerminating {
+				continue
+			}
+
+			log.Debugf("Taking over namespace %v of the resumed run", namespace.Name)
+			err = adoptNamespace(clientSet, namespace)
+			if err != nil {
+				log.Debugf("Unable to take over namespace %v: %v", namespace.Name, err.Error())
+			}
+		} else if isOrphaned(namespace, host) {
+			log.Infof("Deleting namespace %v left behind by a previous run", namespace.Name)
+			deleteNamespace(clientSet, namespace.Name, nil)
+		}
+	}
+}
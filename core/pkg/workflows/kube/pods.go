@@ -1,36 +1,40 @@
 package kube
 
 import (
+	"crypto/md5"
+	"encoding/hex"
+
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/pkg/api/v1"
 
 	workflowsv1 "github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
 	log "github.com/stackfoundation/sandbox/log"
 )
 
-func cleanupPodIfNecessary(context *podContext) {
-	log.Debugf("Deleting pod %v", context.pod.Name)
-	context.podsClient.Delete(context.pod.Name, &metav1.DeleteOptions{})
-
-	if len(context.services) > 0 {
-		for _, service := range context.services {
-			log.Debugf("Deleting service %v", service.Name)
-			context.serviceClient.Delete(service.Name, &metav1.DeleteOptions{})
-		}
+// CreateAndRunPod Create and run a pod in the specified namespace, according to the given specifications.
+// The namespace is created first, if it doesn't exist yet
+func CreateAndRunPod(namespace *Namespace, creationSpec *PodCreationSpec) error {
+	name, err := namespace.Ensure()
+	if err != nil {
+		return err
 	}
 
-	context.creationSpec.Cleanup.Done()
-}
-
-// CreateAndRunPod Create and run a pod according to the given specifications
-func CreateAndRunPod(clientSet *kubernetes.Clientset, creationSpec *PodCreationSpec) error {
 	context := &podContext{
 		creationSpec:  creationSpec,
-		podsClient:    clientSet.Pods("default"),
-		serviceClient: clientSet.Services("default"),
-		podClosed:     make(chan bool, 2),
+		informer:      namespace.pods(),
+		podsClient:    namespace.clientSet.Pods(name),
+		run:           namespace.run,
+		serviceClient: namespace.clientSet.Services(name),
+	}
+
+	if creationSpec.PullSecret != nil {
+		secret, err := namespace.pullSecret(creationSpec.PullSecret)
+		if err != nil {
+			return err
+		}
+
+		context.pullSecret = secret
 	}
 
 	containerName := workflowsv1.GenerateContainerName()
@@ -41,16 +45,30 @@ func CreateAndRunPod(clientSet *kubernetes.Clientset, creationSpec *PodCreationS
 		cleanupPodIfNecessary(context)
 	}()
 
-	err := createPod(context, containerName)
-	if err != nil {
-		return err
+	if creationSpec.Reattach {
+		err = reattachPod(context)
+		if err != nil {
+			return err
+		}
+	}
+
+	if context.pod == nil {
+		err = createPod(context, containerName)
+		if err != nil {
+			return err
+		}
+
+		log.Debugf("Created pod %v", context.pod.Name)
 	}
 
-	log.Debugf("Created pod %v", context.pod.Name)
+	if creationSpec.Listener != nil {
+		creationSpec.Listener.Created()
+	}
 
 	printer := &podLogPrinter{
 		podsClient:       context.podsClient,
 		logPrefix:        creationSpec.LogPrefix,
+		output:           creationSpec.Output,
 		variableReceiver: creationSpec.VariableReceiver,
 		workflowReceiver: creationSpec.WorkflowReceiver,
 	}
@@ -59,22 +77,55 @@ func CreateAndRunPod(clientSet *kubernetes.Clientset, creationSpec *PodCreationS
 	return nil
 }
 
-func createPod(context *podContext, containerName string) error {
-	creationSpec := context.creationSpec
+// stepHash Get the hash of the ID of a step, which the pod of the step is labelled with, as label values
+// can't contain the paths of steps
+func stepHash(stepID string) string {
+	hash := md5.Sum([]byte(stepID))
+	return hex.EncodeToString(hash[:])
+}
 
-	mounts, podVolumes := createVolumes(creationSpec.Volumes)
-	environment := createEnvironment(creationSpec.Environment)
-	readinessProbe := createProbe(creationSpec.Readiness)
-	healthProbe := createProbe(creationSpec.Health)
+func podLabels(creationSpec *PodCreationSpec, run string) map[string]string {
+	labels := make(map[string]string, 3)
+
+	if len(run) > 0 {
+		labels[runLabel] = run
 
-	var labels map[string]string
+		if len(creationSpec.StepID) > 0 {
+			labels[stepLabel] = stepHash(creationSpec.StepID)
+		}
+	}
 
 	if len(creationSpec.Ports) > 0 {
-		labels = make(map[string]string, 1)
 		labels[serviceNameKey] = workflowsv1.GenerateServiceAssociation()
 	}
 
-	pod, err := context.podsClient.Create(&v1.Pod{
+	return labels
+}
+
+func pullPolicy(creationSpec *PodCreationSpec) v1.PullPolicy {
+	if len(creationSpec.PullPolicy) > 0 {
+		return creationSpec.PullPolicy
+	}
+
+	return v1.PullIfNotPresent
+}
+
+func pullSecrets(pullSecret string) []v1.LocalObjectReference {
+	if len(pullSecret) > 0 {
+		return []v1.LocalObjectReference{{Name: pullSecret}}
+	}
+
+	return nil
+}
+
+func podManifest(creationSpec *PodCreationSpec, containerName string, pullSecret string,
+	labels map[string]string) *v1.Pod {
+	mounts, podVolumes := createVolumes(creationSpec.Volumes)
+	environment := createEnvironment(creationSpec.Environment)
+	readinessProbe := createProbe(creationSpec.Readiness)
+	healthProbe := createProbe(creationSpec.Health)
+
+	return &v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "sbox-",
 			Labels:       labels,
@@ -85,7 +136,7 @@ func createPod(context *podContext, containerName string) error {
 					Name:            containerName,
 					Image:           creationSpec.Image,
 					Command:         creationSpec.Command,
-					ImagePullPolicy: v1.PullIfNotPresent,
+					ImagePullPolicy: pullPolicy(creationSpec),
 					VolumeMounts:    mounts,
 					Env:             environment,
 					ReadinessProbe:  readinessProbe,
@@ -100,10 +151,30 @@ func createPod(context *podContext, containerName string) error {
 					},
 				},
 			},
-			Volumes:       podVolumes,
-			RestartPolicy: v1.RestartPolicyNever,
+			ImagePullSecrets:              pullSecrets(pullSecret),
+			Volumes:                       podVolumes,
+			RestartPolicy:                 v1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: gracePeriod(creationSpec.Grace),
 		},
-	})
+	}
+}
+
+// PodManifests Get the pod and services which would be created for the given specifications, without
+// creating them
+func PodManifests(creationSpec *PodCreationSpec) (*v1.Pod, []*v1.Service) {
+	labels := podLabels(creationSpec, "")
+
+	pod := podManifest(creationSpec, workflowsv1.GenerateContainerName(), "", labels)
+	services := serviceManifests(creationSpec, labels)
+
+	return pod, services
+}
+
+func createPod(context *podContext, containerName string) error {
+	creationSpec := context.creationSpec
+	labels := podLabels(creationSpec, context.run)
+
+	pod, err := context.podsClient.Create(podManifest(creationSpec, containerName, context.pullSecret, labels))
 	if err != nil {
 		return err
 	}
@@ -121,3 +192,74 @@ func createPod(context *podContext, containerName string) error {
 
 	return nil
 }
+
+// findStepPod Find the latest pod created for a step in the run, which isn't being deleted
+func findStepPod(context *podContext) (*v1.Pod, error) {
+	selector := runLabel + "=" + context.run + "," + stepLabel + "=" + stepHash(context.creationSpec.StepID)
+
+	pods, err := context.podsClient.List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *v1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		if latest == nil || latest.CreationTimestamp.Time.Before(pod.CreationTimestamp.Time) {
+			latest = pod
+		}
+	}
+
+	return latest, nil
+}
+
+// findPodServices Find the services which were created to expose the ports of a pod
+func findPodServices(context *podContext, pod *v1.Pod) ([]*v1.Service, error) {
+	association := pod.Labels[serviceNameKey]
+	if len(association) == 0 {
+		return nil, nil
+	}
+
+	services, err := context.serviceClient.List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var podServices []*v1.Service
+	for i := range services.Items {
+		service := &services.Items[i]
+		if service.Spec.Selector[serviceNameKey] == association {
+			podServices = append(podServices, service)
+		}
+	}
+
+	return podServices, nil
+}
+
+// reattachPod Follow the pod which was created for a step by the process which ran the run before it was
+// resumed, along with its services, if the pod still exists. Otherwise, the step is run again in a new pod
+func reattachPod(context *podContext) error {
+	pod, err := findStepPod(context)
+	if err != nil {
+		return err
+	}
+
+	if pod == nil {
+		log.Infof("The pod of step %v no longer exists, running the step again", context.creationSpec.LogPrefix)
+		return nil
+	}
+
+	services, err := findPodServices(context, pod)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Reattaching to pod %v of step %v", pod.Name, context.creationSpec.LogPrefix)
+	context.pod = pod
+	context.services = services
+	return nil
+}
@@ -0,0 +1,10 @@
+// +build !windows
+
+package kube
+
+import "syscall"
+
+func isProcessRunning(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
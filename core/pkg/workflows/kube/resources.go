@@ -1,8 +1,6 @@
 package kube
 
 import (
-	"errors"
-
 	workflowsv1 "github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
 	log "github.com/stackfoundation/sandbox/log"
 	extensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
@@ -62,99 +60,112 @@ func DeleteWorkflow(client *rest.RESTClient, workflow *workflowsv1.Workflow) err
 		Error()
 }
 
+const maxUpdateAttempts = 5
+
 // WorkflowUpdater A function which updates the given workflow in some way
 type WorkflowUpdater func(*workflowsv1.Workflow)
 
-// UpdateWorkflow Updates a workflow resource, re-syncing if necessary
-func UpdateWorkflow(client *rest.RESTClient, workflow *workflowsv1.Workflow, updater WorkflowUpdater) error {
-	updater(workflow)
+// GetWorkflow Get a workflow resource
+func GetWorkflow(client *rest.RESTClient, namespace string, name string) (*workflowsv1.Workflow, error) {
+	var workflow workflowsv1.Workflow
+	err := client.Get().
+		Name(name).
+		Namespace(namespace).
+		Resource(workflowsv1.WorkflowsPluralName).
+		Do().
+		Into(&workflow)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workflow, nil
+}
+
+// ListWorkflows List the workflow resources in a namespace
+func ListWorkflows(client *rest.RESTClient, namespace string) ([]workflowsv1.Workflow, error) {
+	var workflows workflowsv1.WorkflowList
+	err := client.Get().
+		Namespace(namespace).
+		Resource(workflowsv1.WorkflowsPluralName).
+		Do().
+		Into(&workflows)
+	if err != nil {
+		return nil, err
+	}
+
+	return workflows.Items, nil
+}
 
+func putWorkflow(client *rest.RESTClient, workflow *workflowsv1.Workflow) (*workflowsv1.Workflow, error) {
+	var updated workflowsv1.Workflow
 	err := client.Put().
 		Name(workflow.ObjectMeta.Name).
 		Namespace(workflow.ObjectMeta.Namespace).
 		Resource(workflowsv1.WorkflowsPluralName).
 		Body(workflow).
 		Do().
-		Error()
+		Into(&updated)
+	if err != nil {
+		return nil, err
+	}
 
-	if kubeerr.IsConflict(err) {
-		log.Debugf(`Workflow "%v" was changed, need to re-sync`, workflow.ObjectMeta.Name)
-		result := client.Get().
-			Name(workflow.ObjectMeta.Name).
-			Namespace(workflow.ObjectMeta.Namespace).
-			Resource(workflowsv1.WorkflowsPluralName).
-			Do()
+	return &updated, nil
+}
 
-		err = result.Error()
-		if err != nil {
-			return err
-		}
+// UpdateWorkflow Updates a workflow resource, re-syncing and applying the update again whenever the resource
+// was changed in the meantime. The resource as it was stored is returned
+func UpdateWorkflow(client *rest.RESTClient, workflow *workflowsv1.Workflow, updater WorkflowUpdater) (*workflowsv1.Workflow, error) {
+	for attempt := 1; ; attempt++ {
+		updater(workflow)
 
-		updatedWorkflowRaw, err := result.Get()
-		if err != nil {
-			return err
+		updated, err := putWorkflow(client, workflow)
+		if err == nil {
+			log.Debugf(`Workflow "%v" updated`, workflow.ObjectMeta.Name)
+			return updated, nil
 		}
 
-		updatedWorkflow, ok := updatedWorkflowRaw.(*workflowsv1.Workflow)
-		if !ok {
-			return errors.New(`Invalid workflow retrieved while trying to re-sync "` +
-				workflow.ObjectMeta.Name + `"`)
+		if !kubeerr.IsConflict(err) || attempt >= maxUpdateAttempts {
+			return nil, err
 		}
 
-		updater(updatedWorkflow)
-
-		err = client.Put().
-			Name(workflow.ObjectMeta.Name).
-			Namespace(workflow.ObjectMeta.Namespace).
-			Resource(workflowsv1.WorkflowsPluralName).
-			Body(workflow).
-			Do().
-			Error()
+		log.Debugf(`Workflow "%v" was changed, need to re-sync`, workflow.ObjectMeta.Name)
+		workflow, err = GetWorkflow(client, workflow.ObjectMeta.Namespace, workflow.ObjectMeta.Name)
 		if err != nil {
-			return err
+			return nil, err
 		}
-	} else if err != nil {
-		return err
 	}
-
-	log.Debugf(`Workflow "%v" updated`, workflow.ObjectMeta.Name)
-	return nil
 }
 
-func uploadWorkflow(client *rest.RESTClient, workflow *workflowsv1.Workflow) error {
-	return client.Post().
-		Name(workflow.ObjectMeta.Name).
+func uploadWorkflow(client *rest.RESTClient, workflow *workflowsv1.Workflow) (*workflowsv1.Workflow, error) {
+	var uploaded workflowsv1.Workflow
+	err := client.Post().
 		Namespace(workflow.ObjectMeta.Namespace).
 		Resource(workflowsv1.WorkflowsPluralName).
 		Body(workflow).
 		Do().
-		Error()
-}
-
-// UploadWorkflow Upload a workflow resource, deleting an existing one, if present
-func UploadWorkflow(workflow *workflowsv1.Workflow) error {
-	log.Debugf(`Uploading workflow "%v"`, workflow.ObjectMeta.Name)
-
-	client, err := CreateWorkflowsClient()
+		Into(&uploaded)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = uploadWorkflow(client, workflow)
+	return &uploaded, nil
+}
+
+// UploadWorkflow Upload a workflow resource, deleting an existing one, if present. The resource as it was
+// stored is returned
+func UploadWorkflow(client *rest.RESTClient, workflow *workflowsv1.Workflow) (*workflowsv1.Workflow, error) {
+	log.Debugf(`Uploading workflow "%v"`, workflow.ObjectMeta.Name)
 
-	if kubeerr.IsConflict(err) {
+	uploaded, err := uploadWorkflow(client, workflow)
+	if kubeerr.IsAlreadyExists(err) {
 		log.Debugf(`A workflow with name "%v" already exists, deleting it`, workflow.ObjectMeta.Name)
 		err = DeleteWorkflow(client, workflow)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		err = uploadWorkflow(client, workflow)
+		uploaded, err = uploadWorkflow(client, workflow)
 	}
 
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return uploaded, err
 }
@@ -0,0 +1,79 @@
+package kube
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	workflowsv1 "github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	log "github.com/stackfoundation/sandbox/log"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+type dockerConfigEntry struct {
+	Auth     string `json:"auth"`
+	Password string `json:"password"`
+	Username string `json:"username"`
+}
+
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+func pullSecretName(credentials *workflowsv1.RegistryCredentials) string {
+	return "sbox-registry-" + projectHash(credentials.Server + "\x00" + credentials.Username)[:8]
+}
+
+func pullSecretManifest(name string, credentials *workflowsv1.RegistryCredentials) (*v1.Secret, error) {
+	auth := credentials.Username + ":" + credentials.Password
+
+	content, err := json.Marshal(&dockerConfigJSON{
+		Auths: map[string]dockerConfigEntry{
+			credentials.Server: {
+				Auth:     base64.StdEncoding.EncodeToString([]byte(auth)),
+				Password: credentials.Password,
+				Username: credentials.Username,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Type: v1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			v1.DockerConfigJsonKey: content,
+		},
+	}, nil
+}
+
+// pullSecret Create a secret which pods in the namespace can pull images with, using the specified
+// credentials, if it hasn't been created yet, and get its name
+func (n *Namespace) pullSecret(credentials *workflowsv1.RegistryCredentials) (string, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	name := pullSecretName(credentials)
+	if n.pullSecrets[name] {
+		return name, nil
+	}
+
+	secret, err := pullSecretManifest(name, credentials)
+	if err != nil {
+		return "", err
+	}
+
+	log.Debugf("Creating secret %v to pull images from %v", name, credentials.Server)
+	_, err = n.clientSet.Secrets(n.name).Create(secret)
+	if err != nil && !kubeerr.IsAlreadyExists(err) {
+		return "", err
+	}
+
+	n.pullSecrets[name] = true
+	return name, nil
+}
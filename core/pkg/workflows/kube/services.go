@@ -5,8 +5,10 @@ import (
 
 	workflowsv1 "github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
 	log "github.com/stackfoundation/sandbox/log"
+	kubeerr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/pkg/api/v1"
 )
 
@@ -54,7 +56,7 @@ func createServicePort(port workflowsv1.Port) v1.ServicePort {
 	return servicePort
 }
 
-func createService(context *podContext, port workflowsv1.Port, labels map[string]string) (*v1.Service, error) {
+func serviceManifest(port workflowsv1.Port, labels map[string]string) *v1.Service {
 	servicePort := createServicePort(port)
 
 	serviceType := v1.ServiceTypeClusterIP
@@ -67,8 +69,7 @@ func createService(context *podContext, port workflowsv1.Port, labels map[string
 		serviceName = workflowsv1.GenerateServiceName()
 	}
 
-	log.Debugf("Creating service %v", serviceName)
-	return context.serviceClient.Create(&v1.Service{
+	return &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: serviceName,
 		},
@@ -77,15 +78,25 @@ func createService(context *podContext, port workflowsv1.Port, labels map[string
 			Selector: labels,
 			Ports:    []v1.ServicePort{servicePort},
 		},
-	})
+	}
 }
 
-func createServices(context *podContext, labels map[string]string) ([]*v1.Service, error) {
-	creationSpec := context.creationSpec
-
+func serviceManifests(creationSpec *PodCreationSpec, labels map[string]string) []*v1.Service {
 	services := make([]*v1.Service, 0, len(creationSpec.Ports))
 	for _, port := range creationSpec.Ports {
-		service, err := createService(context, port, labels)
+		services = append(services, serviceManifest(port, labels))
+	}
+
+	return services
+}
+
+func createServices(context *podContext, labels map[string]string) ([]*v1.Service, error) {
+	manifests := serviceManifests(context.creationSpec, labels)
+
+	services := make([]*v1.Service, 0, len(manifests))
+	for _, manifest := range manifests {
+		log.Debugf("Creating service %v", manifest.Name)
+		service, err := context.serviceClient.Create(manifest)
 		if err != nil {
 			return nil, err
 		}
@@ -95,3 +106,51 @@ func createServices(context *podContext, labels map[string]string) ([]*v1.Servic
 
 	return services, nil
 }
+
+func isLeftoverService(clientSet *kubernetes.Clientset, service *v1.Service) (bool, error) {
+	association, ok := service.Spec.Selector[serviceNameKey]
+	if !ok || !strings.HasPrefix(association, "assoc-") {
+		return false, nil
+	}
+
+	pods, err := clientSet.Pods(service.Namespace).List(metav1.ListOptions{
+		LabelSelector: serviceNameKey + "=" + association,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(pods.Items) == 0, nil
+}
+
+// LeftoverServices Find the services which were created for steps whose pods no longer exist
+func LeftoverServices(clientSet *kubernetes.Clientset) ([]v1.Service, error) {
+	services, err := clientSet.Services(v1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var leftovers []v1.Service
+	for _, service := range services.Items {
+		leftover, err := isLeftoverService(clientSet, &service)
+		if err != nil {
+			return nil, err
+		}
+
+		if leftover {
+			leftovers = append(leftovers, service)
+		}
+	}
+
+	return leftovers, nil
+}
+
+// DeleteService Delete the specified service, if it still exists
+func DeleteService(clientSet *kubernetes.Clientset, service *v1.Service) error {
+	err := clientSet.Services(service.Namespace).Delete(service.Name, &metav1.DeleteOptions{})
+	if err != nil && !kubeerr.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
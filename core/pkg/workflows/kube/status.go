@@ -62,21 +62,26 @@ func isPodReady(pod *v1.Pod) bool {
 	return false
 }
 
+// Reasons a container is left waiting when its image can't be pulled
+var pullFailureReasons = map[string]bool{
+	"ErrImagePull":      true,
+	"ErrImageNeverPull": true,
+	"ImagePullBackOff":  true,
+	"InvalidImageName":  true,
+}
+
 func isPullFail(pod *v1.Pod) (bool, string) {
-	if len(pod.Status.ContainerStatuses) > 0 {
-		for i := 0; i < len(pod.Status.ContainerStatuses); i++ {
-			if pod.Status.ContainerStatuses[i].State.Waiting != nil {
-				if pod.Status.ContainerStatuses[i].State.Waiting.Reason == "ErrImagePull" {
-					message := pod.Status.ContainerStatuses[i].State.Waiting.Message
-					if len(message) > 0 {
-						message = message + " - "
-					}
-
-					message = message + "Error pulling image"
-
-					return false, message
-				}
+	for i := 0; i < len(pod.Status.ContainerStatuses); i++ {
+		status := &pod.Status.ContainerStatuses[i]
+		waiting := status.State.Waiting
+
+		if waiting != nil && pullFailureReasons[waiting.Reason] {
+			message := "Error pulling image " + status.Image + " (" + waiting.Reason + ")"
+			if len(waiting.Message) > 0 {
+				message = message + ": " + waiting.Message
 			}
+
+			return true, message
 		}
 	}
 
@@ -2,6 +2,7 @@ package kube
 
 import (
 	"context"
+	"io"
 	"sync"
 
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/properties"
@@ -14,6 +15,8 @@ const serviceNameKey = "svc"
 
 // PodListener Listener which listens for pod events
 type PodListener interface {
+	Created()
+	Started()
 	Container(containerID string)
 	Ready()
 	Done(failed bool, message string)
@@ -25,13 +28,19 @@ type PodCreationSpec struct {
 	Command          []string
 	Context          context.Context
 	Environment      *properties.Properties
+	Grace            string
 	Health           *workflowsv1.HealthCheck
 	Image            string
 	Name             string
 	LogPrefix        string
+	Output           io.Writer
 	Ports            []workflowsv1.Port
+	PullPolicy       v1.PullPolicy
+	PullSecret       *workflowsv1.RegistryCredentials
 	Readiness        *workflowsv1.HealthCheck
+	Reattach         bool
 	Listener         PodListener
+	StepID           string
 	VariableReceiver func(string, string)
 	Volumes          []workflowsv1.Volume
 	WorkflowReceiver func(string)
@@ -39,9 +48,12 @@ type PodCreationSpec struct {
 
 type podContext struct {
 	creationSpec  *PodCreationSpec
+	informer      *podInformer
 	podsClient    corev1.PodInterface
 	pod           *v1.Pod
+	pullSecret    string
+	run           string
 	services      []*v1.Service
 	serviceClient corev1.ServiceInterface
-	podClosed     chan bool
+	podFinished   int32
 }
@@ -1,72 +1,82 @@
 package kube
 
 import (
-	"fmt"
 	"sync/atomic"
 
 	log "github.com/stackfoundation/sandbox/log"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/pkg/api/v1"
 )
 
-func waitForPod(context *podContext, logPrinter *podLogPrinter) {
-	log.Debugf("Starting watch on pod %v", context.pod.Name)
-	podWatch, err := logPrinter.podsClient.Watch(metav1.ListOptions{Watch: true})
-	if err != nil {
-		fmt.Println(err.Error())
-		cleanupPodIfNecessary(context)
+func podDeleted(context *podContext) {
+	if context.creationSpec.Context.Err() != nil {
 		return
 	}
 
+	log.Debugf("Pod %v was deleted before it finished", context.pod.Name)
+	if context.creationSpec.Listener != nil {
+		context.creationSpec.Listener.Done(true, "Pod was deleted")
+	}
+}
+
+func waitForPod(context *podContext, logPrinter *podLogPrinter) {
+	log.Debugf("Waiting for events from pod %v", context.pod.Name)
+	events := context.informer.watch(context.pod)
+	defer context.informer.forget(context.pod)
+
 	var containerAvailable int32
+	var containerStarted int32
 	var podReady int32
 
-	channel := podWatch.ResultChan()
-	for event := range channel {
-		eventPod, ok := event.Object.(*v1.Pod)
-		if ok && eventPod.Name == context.pod.Name {
-			logPrinter.printLogs(eventPod)
+	for event := range events {
+		if event.deleted {
+			logPrinter.close()
+			podDeleted(context)
+			return
+		}
 
-			listener := context.creationSpec.Listener
-			if listener != nil {
-				containerID := getContainerID(&eventPod.Status)
-				if len(containerID) > 0 {
-					if atomic.CompareAndSwapInt32(&containerAvailable, 0, 1) {
-						listener.Container(containerID)
-					}
-				}
+		eventPod := event.pod
+		logPrinter.printLogs(eventPod)
 
-				if isPodReady(eventPod) {
-					if atomic.CompareAndSwapInt32(&podReady, 0, 1) {
-						listener.Ready()
-					}
+		listener := context.creationSpec.Listener
+		if listener != nil {
+			containerID := getContainerID(&eventPod.Status)
+			if len(containerID) > 0 {
+				if atomic.CompareAndSwapInt32(&containerAvailable, 0, 1) {
+					listener.Container(containerID)
 				}
+			}
 
-				pullFailed, message := isPullFail(eventPod)
-				if pullFailed {
-					context.podClosed <- true
-					if listener != nil {
-						listener.Done(true, message)
-					}
+			if isContainerRunning(&eventPod.Status) || isContainerTerminated(&eventPod.Status) {
+				if atomic.CompareAndSwapInt32(&containerStarted, 0, 1) {
+					listener.Started()
+				}
+			}
 
-					break
+			if isPodReady(eventPod) {
+				if atomic.CompareAndSwapInt32(&podReady, 0, 1) {
+					listener.Ready()
 				}
 			}
 
-			if isPodFinished(eventPod) {
-				failed := eventPod.Status.Phase == v1.PodFailed
-				message := eventPod.Status.Message + " (" + eventPod.Status.Reason + ")"
+			pullFailed, message := isPullFail(eventPod)
+			if pullFailed {
 				logPrinter.close()
-				context.podClosed <- true
+				listener.Done(true, message)
+				return
+			}
+		}
 
-				if listener != nil {
-					listener.Done(failed, message)
-				}
+		if isPodFinished(eventPod) {
+			atomic.StoreInt32(&context.podFinished, 1)
+			failed := eventPod.Status.Phase == v1.PodFailed
+			message := eventPod.Status.Message + " (" + eventPod.Status.Reason + ")"
+			logPrinter.close()
 
-				break
+			if listener != nil {
+				listener.Done(failed, message)
 			}
+
+			return
 		}
 	}
-
-	close(context.podClosed)
 }
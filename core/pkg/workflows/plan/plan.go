@@ -0,0 +1,80 @@
+package plan
+
+import (
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/coordinator"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/image"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/preparation"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/run"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+func planPodStep(workflow *v1.Workflow, step *v1.WorkflowStep, selector []int, stepPlan *StepPlan) error {
+	imagePlan, err := image.PlanStepImage(&workflow.Spec, step)
+	if err != nil {
+		return err
+	}
+
+	stepPlan.Image = imagePlan
+
+	if len(step.State.GeneratedImage) < 1 {
+		step.State.GeneratedImage = "<image of step " + step.StepName(selector) + ">"
+	}
+
+	spec := run.PlanPodStep(workflow, step, selector)
+	stepPlan.Command = spec.Command
+	stepPlan.Environment = spec.Environment.Map()
+	stepPlan.Pod, stepPlan.Services = coordinator.PlanStep(spec)
+
+	return nil
+}
+
+func planStep(workflow *v1.Workflow, step *v1.WorkflowStep, selector []int) StepPlan {
+	stepPlan := StepPlan{
+		Step: workflow.StepPath(selector),
+		Type: step.Type(),
+	}
+
+	if step.IsSkipped() {
+		stepPlan.Skipped = true
+		return stepPlan
+	}
+
+	err := preparation.PrepareStepIfNecessary(workflow, step, selector)
+	if err != nil {
+		stepPlan.Error = err.Error()
+	}
+
+	if step.RequiresBuild() {
+		err = planPodStep(workflow, step, selector, &stepPlan)
+		if err != nil {
+			stepPlan.Error = err.Error()
+		}
+	} else if step.External != nil {
+		stepPlan.Workflow = step.External.Workflow
+	}
+
+	publish := step.PublishOptions()
+	if publish != nil {
+		stepPlan.Tags = publish.Tags
+	}
+
+	return stepPlan
+}
+
+// Plan Plan how a workflow would run, performing variable expansion and validation on each of its
+// steps, but without building any images or running any steps
+func Plan(workflow *v1.Workflow) *WorkflowPlan {
+	workflowPlan := &WorkflowPlan{
+		Workflow: workflow.Name,
+	}
+
+	selector := workflow.IncrementStepSelector([]int{})
+	for len(selector) > 0 {
+		step := workflow.Select(selector)
+		workflowPlan.Steps = append(workflowPlan.Steps, planStep(workflow, step, selector))
+
+		selector = workflow.IncrementStepSelector(selector)
+	}
+
+	return workflowPlan
+}
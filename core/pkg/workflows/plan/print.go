@@ -0,0 +1,118 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// JSON Write the plan as JSON
+func (p *WorkflowPlan) JSON(writer io.Writer) error {
+	content, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(writer, string(content))
+	return err
+}
+
+// YAML Write the plan as YAML
+func (p *WorkflowPlan) YAML(writer io.Writer) error {
+	content, err := yaml.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(content)
+	return err
+}
+
+func writeIndented(writer io.Writer, content string) {
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		fmt.Fprintln(writer, "    "+line)
+	}
+}
+
+func writeSection(writer io.Writer, title string, content string) {
+	if len(content) > 0 {
+		fmt.Fprintln(writer, "  "+title+":")
+		writeIndented(writer, content)
+	}
+}
+
+func writeEnvironment(writer io.Writer, environment map[string]string) {
+	if len(environment) > 0 {
+		names := make([]string, 0, len(environment))
+		for name := range environment {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		fmt.Fprintln(writer, "  Environment:")
+		for _, name := range names {
+			fmt.Fprintln(writer, "    "+name+"="+environment[name])
+		}
+	}
+}
+
+func writeManifest(writer io.Writer, title string, manifest interface{}) {
+	content, err := yaml.Marshal(manifest)
+	if err == nil {
+		writeSection(writer, title, string(content))
+	}
+}
+
+func (s *StepPlan) write(writer io.Writer) {
+	if s.Skipped {
+		fmt.Fprintf(writer, "Step %v (%v): skipped\n", s.Step, s.Type)
+		return
+	}
+
+	fmt.Fprintf(writer, "Step %v (%v):\n", s.Step, s.Type)
+	writeSection(writer, "Error", s.Error)
+
+	if len(s.Workflow) > 0 {
+		fmt.Fprintln(writer, "  Calls workflow: "+s.Workflow)
+	}
+
+	if s.Image != nil {
+		if s.Image.Context != nil {
+			fmt.Fprintf(writer, "  Build context: %v (%v files, %v bytes)\n",
+				s.Image.Context.Directory, len(s.Image.Context.Files), s.Image.Context.Size)
+		}
+
+		writeSection(writer, "Dockerfile", s.Image.Dockerfile)
+		writeSection(writer, "Script ("+s.Image.ScriptName+")", s.Image.Script)
+	}
+
+	if len(s.Command) > 0 {
+		fmt.Fprintln(writer, "  Command: "+strings.Join(s.Command, " "))
+	}
+
+	writeEnvironment(writer, s.Environment)
+
+	if s.Pod != nil {
+		writeManifest(writer, "Pod", s.Pod)
+	}
+
+	for _, service := range s.Services {
+		writeManifest(writer, "Service", service)
+	}
+}
+
+// Text Write the plan as human-readable text
+func (p *WorkflowPlan) Text(writer io.Writer) error {
+	fmt.Fprintf(writer, "Plan for workflow %v:\n", p.Workflow)
+	for i := range p.Steps {
+		fmt.Fprintln(writer)
+		p.Steps[i].write(writer)
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package plan
+
+import (
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/image"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// StepPlan Plan of how a single step of a workflow would run
+type StepPlan struct {
+	Step        string            `json:"step"`
+	Type        string            `json:"type"`
+	Skipped     bool              `json:"skipped,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	Workflow    string            `json:"workflow,omitempty"`
+	Image       *image.Plan       `json:"image,omitempty"`
+	Command     []string          `json:"command,omitempty"`
+	Environment map[string]string `json:"environment,omitempty"`
+	Pod         *v1.Pod           `json:"pod,omitempty"`
+	Services    []*v1.Service     `json:"services,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+}
+
+// WorkflowPlan Plan of how a workflow would run
+type WorkflowPlan struct {
+	Workflow string     `json:"workflow"`
+	Steps    []StepPlan `json:"steps"`
+}
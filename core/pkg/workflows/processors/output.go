@@ -0,0 +1,36 @@
+package processors
+
+import (
+	"io"
+
+	coreio "github.com/stackfoundation/sandbox/core/pkg/io"
+)
+
+// OutputOptions Options for processing the output of a step
+type OutputOptions struct {
+	LogPrefix        string
+	Output           io.Writer
+	VariableReceiver func(string, string)
+	WorkflowReceiver func(string)
+}
+
+// ProcessOutput Wrap the output stream of a step with the processors which capture and prefix its output
+func ProcessOutput(stream io.ReadCloser, options *OutputOptions) io.ReadCloser {
+	if options.Output != nil {
+		stream = coreio.TeeReadCloser(stream, options.Output)
+	}
+
+	if options.VariableReceiver != nil {
+		stream = NewVariableDetector(stream, options.VariableReceiver)
+	}
+
+	if options.WorkflowReceiver != nil {
+		stream = NewWorkflowDetector(stream, options.WorkflowReceiver)
+	}
+
+	if len(options.LogPrefix) > 0 {
+		stream = NewPrefixer(stream, "\x1b[30;1m["+options.LogPrefix+"]\x1b[0m ")
+	}
+
+	return stream
+}
@@ -37,7 +37,8 @@ func cleanVariableValue(rawValue string) string {
 	return rawValue
 }
 
-func extractVariable(line []byte) (string, string) {
+// ExtractVariable Extract the name and value of a variable declared in a line, if the line declares one
+func ExtractVariable(line []byte) (string, string) {
 	separator := bytes.IndexByte(line, '=')
 	if separator > 0 {
 		rawDeclaration := line[:separator]
@@ -64,7 +65,7 @@ func NewVariableDetector(reader io.ReadCloser, receiver func(string, string)) io
 	lineReceiver := func([]byte) {}
 	if receiver != nil {
 		lineReceiver = func(line []byte) {
-			name, value := extractVariable(line)
+			name, value := ExtractVariable(line)
 			if len(name) > 0 {
 				receiver(name, value)
 			}
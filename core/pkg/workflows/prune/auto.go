@@ -0,0 +1,156 @@
+package prune
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/engine-api/client"
+	"github.com/docker/go-units"
+
+	"github.com/stackfoundation/sandbox/log"
+)
+
+// autoPruneInterval The least amount of time between two checks of disk usage by an auto-pruner
+const autoPruneInterval = time.Minute
+
+// DiskUsage Measure the disk space used (in bytes), given the images generated by Sandbox
+type DiskUsage func(images []Artifact) (int64, error)
+
+// AutoPruner Removes the oldest images generated by Sandbox, across all projects, once disk usage goes above
+// a threshold. Disk usage is checked at most once a minute
+type AutoPruner struct {
+	checked   time.Time
+	client    *client.Client
+	diskUsage DiskUsage
+	mutex     sync.Mutex
+	started   time.Time
+	threshold int64
+}
+
+type byCreated []Artifact
+
+func (artifacts byCreated) Len() int {
+	return len(artifacts)
+}
+
+func (artifacts byCreated) Less(i, j int) bool {
+	return artifacts[i].Created.Before(artifacts[j].Created)
+}
+
+func (artifacts byCreated) Swap(i, j int) {
+	artifacts[i], artifacts[j] = artifacts[j], artifacts[i]
+}
+
+func totalSize(artifacts []Artifact) int64 {
+	var size int64
+	for _, artifact := range artifacts {
+		size += artifact.Size
+	}
+
+	return size
+}
+
+// ImageDiskUsage Measure disk usage as the total size of the images generated by Sandbox. This is used when
+// images are built on a Docker daemon whose disk can't be measured, which makes the threshold a budget for
+// generated images rather than a limit on the usage of the whole disk
+func ImageDiskUsage(images []Artifact) (int64, error) {
+	return totalSize(images), nil
+}
+
+// NewAutoPruner Create an auto-pruner which removes generated images once the disk usage measured by
+// diskUsage goes above the threshold (in bytes)
+func NewAutoPruner(dockerClient *client.Client, threshold int64, diskUsage DiskUsage) *AutoPruner {
+	return &AutoPruner{
+		client:    dockerClient,
+		diskUsage: diskUsage,
+		started:   time.Now().Truncate(time.Second),
+		threshold: threshold,
+	}
+}
+
+func isKept(image *Artifact, kept map[string]bool) bool {
+	if kept[image.ID] {
+		return true
+	}
+
+	for _, reference := range image.references {
+		if kept[reference] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PruneIfNecessary Remove the oldest generated images if disk usage is above the threshold, unless it was
+// already checked less than a minute ago. Images created after the pruner was, and the kept images, are
+// never removed, as the workflow being run can still need them. Containers are left alone, as the stopped
+// containers of steps can still be needed by runs in progress, so images used by containers are kept until
+// sbox prune removes the containers. Pruning is best-effort, and failures are only logged
+func (pruner *AutoPruner) PruneIfNecessary(ctx context.Context, kept []string) {
+	pruner.mutex.Lock()
+	defer pruner.mutex.Unlock()
+
+	if time.Since(pruner.checked) < autoPruneInterval {
+		return
+	}
+
+	pruner.checked = time.Now()
+
+	err := pruner.prune(ctx, kept)
+	if err != nil {
+		log.Debugf("Unable to prune generated images: %v", err.Error())
+	}
+}
+
+func (pruner *AutoPruner) prune(ctx context.Context, kept []string) error {
+	images, err := findImages(ctx, pruner.client, func(labels map[string]string, created time.Time) bool {
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	used, err := pruner.diskUsage(images)
+	if err != nil {
+		return err
+	}
+
+	if used <= pruner.threshold {
+		return nil
+	}
+
+	log.Infof("Disk usage of %v is more than the prune threshold of %v. Removing the oldest generated images",
+		units.HumanSize(float64(used)), units.HumanSize(float64(pruner.threshold)))
+
+	keptReferences := make(map[string]bool)
+	for _, reference := range kept {
+		keptReferences[reference] = true
+	}
+
+	report := &Report{}
+
+	sort.Sort(byCreated(images))
+	for i := 0; i < len(images) && used > pruner.threshold; i++ {
+		image := &images[i]
+		if !image.Created.Before(pruner.started) || isKept(image, keptReferences) {
+			continue
+		}
+
+		deleted, err := removeImage(ctx, pruner.client, image)
+		if err != nil {
+			log.Debugf("Unable to remove image %v: %v", image.Name, err.Error())
+			continue
+		}
+
+		report.add(image, deleted)
+		if deleted {
+			used -= image.Size
+		}
+	}
+
+	log.Infof("Removed %v generated artifacts, reclaiming %v", len(report.Artifacts), units.HumanSize(float64(report.Reclaimed)))
+	return nil
+}
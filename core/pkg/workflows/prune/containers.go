@@ -0,0 +1,73 @@
+package prune
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/docker/engine-api/client"
+	"github.com/docker/engine-api/types"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/docker"
+)
+
+func isGeneratedContainer(container *types.Container) bool {
+	if _, labelled := container.Labels[docker.GeneratedLabel]; labelled {
+		return true
+	}
+
+	for _, name := range container.Names {
+		if strings.HasPrefix(name, "/sbox-") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isStopped(container *types.Container) bool {
+	return container.State == "exited" || container.State == "created" || container.State == "dead"
+}
+
+func containerArtifact(container *types.Container) Artifact {
+	name := container.ID
+	if len(container.Names) > 0 {
+		name = strings.TrimPrefix(container.Names[0], "/")
+	}
+
+	return Artifact{
+		Created: time.Unix(container.Created, 0),
+		ID:      container.ID,
+		Kind:    ContainerArtifact,
+		Name:    name,
+		Size:    container.SizeRw,
+	}
+}
+
+func findContainers(ctx context.Context, dockerClient *client.Client, selected func(map[string]string, time.Time) bool) ([]Artifact, error) {
+	containers, err := dockerClient.ContainerList(ctx, types.ContainerListOptions{All: true, Size: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts []Artifact
+	for i := range containers {
+		container := &containers[i]
+		if isGeneratedContainer(container) && isStopped(container) &&
+			selected(container.Labels, time.Unix(container.Created, 0)) {
+			artifacts = append(artifacts, containerArtifact(container))
+		}
+	}
+
+	return artifacts, nil
+}
+
+// removeContainer Remove a stopped container, along with its anonymous volumes
+func removeContainer(ctx context.Context, dockerClient *client.Client, artifact *Artifact) error {
+	err := dockerClient.ContainerRemove(ctx, artifact.ID, types.ContainerRemoveOptions{RemoveVolumes: true})
+	if err != nil && !client.IsErrContainerNotFound(err) {
+		return err
+	}
+
+	return nil
+}
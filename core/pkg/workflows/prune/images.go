@@ -0,0 +1,109 @@
+package prune
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/docker/engine-api/client"
+	"github.com/docker/engine-api/types"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/docker"
+)
+
+func isGeneratedTag(tag string) bool {
+	return strings.HasPrefix(tag, "step:") || strings.Contains(tag, "/sbox-step:")
+}
+
+func generatedTags(image *types.Image) []string {
+	var tags []string
+
+	for _, tag := range image.RepoTags {
+		if isGeneratedTag(tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+func isGeneratedImage(image *types.Image) bool {
+	_, labelled := image.Labels[docker.GeneratedLabel]
+	return labelled || len(generatedTags(image)) > 0
+}
+
+// imageReferences Get the references to remove a generated image by. Only the generated tags of an image
+// are removed, so that an image which has also been tagged by hand is kept. An image without any tags is
+// removed by its ID
+func imageReferences(image *types.Image) []string {
+	generated := generatedTags(image)
+
+	for _, tag := range image.RepoTags {
+		if !isGeneratedTag(tag) && tag != "<none>:<none>" {
+			return generated
+		}
+	}
+
+	if len(generated) > 0 {
+		return generated
+	}
+
+	return []string{image.ID}
+}
+
+func imageArtifact(image *types.Image, references []string) Artifact {
+	return Artifact{
+		Created:    time.Unix(image.Created, 0),
+		ID:         image.ID,
+		Kind:       ImageArtifact,
+		Name:       references[0],
+		Size:       image.Size,
+		references: references,
+	}
+}
+
+func findImages(ctx context.Context, dockerClient *client.Client, selected func(map[string]string, time.Time) bool) ([]Artifact, error) {
+	images, err := dockerClient.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts []Artifact
+	for i := range images {
+		image := &images[i]
+		if !isGeneratedImage(image) || !selected(image.Labels, time.Unix(image.Created, 0)) {
+			continue
+		}
+
+		references := imageReferences(image)
+		if len(references) > 0 {
+			artifacts = append(artifacts, imageArtifact(image, references))
+		}
+	}
+
+	return artifacts, nil
+}
+
+// removeImage Remove the generated references to an image, and get whether the image itself was deleted
+func removeImage(ctx context.Context, dockerClient *client.Client, artifact *Artifact) (bool, error) {
+	var deleted bool
+
+	for _, reference := range artifact.references {
+		deletions, err := dockerClient.ImageRemove(ctx, reference, types.ImageRemoveOptions{PruneChildren: true})
+		if err != nil {
+			if client.IsErrImageNotFound(err) {
+				continue
+			}
+
+			return deleted, err
+		}
+
+		for _, deletion := range deletions {
+			if len(deletion.Deleted) > 0 {
+				deleted = true
+			}
+		}
+	}
+
+	return deleted, nil
+}
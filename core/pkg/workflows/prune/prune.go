@@ -0,0 +1,212 @@
+package prune
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/engine-api/client"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/crd"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/docker"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/kube"
+	workflowsv1 "github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+func (options *Options) selector() func(map[string]string, time.Time) bool {
+	cutoff := time.Now().Add(-options.OlderThan)
+
+	return func(labels map[string]string, created time.Time) bool {
+		if !options.All && labels[docker.ProjectLabel] != options.Project {
+			return false
+		}
+
+		return options.OlderThan <= 0 || created.Before(cutoff)
+	}
+}
+
+func (report *Report) add(artifact *Artifact, reclaimed bool) {
+	report.Artifacts = append(report.Artifacts, *artifact)
+	if reclaimed {
+		report.Reclaimed += artifact.Size
+	}
+}
+
+func pruneContainers(ctx context.Context, dockerClient *client.Client, options *Options, report *Report) error {
+	containers, err := findContainers(ctx, dockerClient, options.selector())
+	if err != nil {
+		return err
+	}
+
+	for i := range containers {
+		container := &containers[i]
+		if !options.DryRun {
+			log.Debugf("Removing container %v", container.Name)
+			err = removeContainer(ctx, dockerClient, container)
+			if err != nil {
+				log.Errorf("Error removing container %v: %v", container.Name, err.Error())
+				continue
+			}
+		}
+
+		report.add(container, true)
+	}
+
+	return nil
+}
+
+func pruneImages(ctx context.Context, dockerClient *client.Client, options *Options, report *Report) error {
+	images, err := findImages(ctx, dockerClient, options.selector())
+	if err != nil {
+		return err
+	}
+
+	for i := range images {
+		image := &images[i]
+		if options.DryRun {
+			report.add(image, true)
+			continue
+		}
+
+		log.Debugf("Removing image %v", image.Name)
+		deleted, err := removeImage(ctx, dockerClient, image)
+		if err != nil {
+			log.Errorf("Error removing image %v: %v", image.Name, err.Error())
+			continue
+		}
+
+		report.add(image, deleted)
+	}
+
+	return nil
+}
+
+func serviceArtifact(service *v1.Service) Artifact {
+	return Artifact{
+		Created: service.CreationTimestamp.Time,
+		ID:      string(service.UID),
+		Kind:    ServiceArtifact,
+		Name:    service.Namespace + "/" + service.Name,
+	}
+}
+
+// pruneServices Delete the services of steps whose pods no longer exist. These are left behind when a run
+// is killed, and are useless to every project, so they are pruned regardless of the project selected
+func pruneServices(clientSet *kubernetes.Clientset, options *Options, report *Report) error {
+	services, err := kube.LeftoverServices(clientSet)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-options.OlderThan)
+	for i := range services {
+		service := &services[i]
+		if options.OlderThan > 0 && !service.CreationTimestamp.Time.Before(cutoff) {
+			continue
+		}
+
+		artifact := serviceArtifact(service)
+		if !options.DryRun {
+			log.Debugf("Deleting service %v", artifact.Name)
+			err = kube.DeleteService(clientSet, service)
+			if err != nil {
+				log.Errorf("Error deleting service %v: %v", artifact.Name, err.Error())
+				continue
+			}
+		}
+
+		report.add(&artifact, false)
+	}
+
+	return nil
+}
+
+func workflowArtifact(workflow *workflowsv1.Workflow) Artifact {
+	return Artifact{
+		Created: workflow.CreationTimestamp.Time,
+		ID:      string(workflow.UID),
+		Kind:    WorkflowArtifact,
+		Name:    workflow.Name,
+	}
+}
+
+// pruneWorkflows Delete the workflow resources mirroring runs of the selected projects which are no longer
+// running. The resources of runs which are still marked as running, because their CLI exited, are only
+// deleted when they are older than the age selected
+func pruneWorkflows(workflowsClient *rest.RESTClient, options *Options, report *Report) error {
+	workflows, err := kube.ListWorkflows(workflowsClient, crd.Namespace)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-options.OlderThan)
+	for i := range workflows {
+		workflow := &workflows[i]
+		if !options.All && workflow.Spec.State.ProjectRoot != options.Project {
+			continue
+		}
+
+		if options.OlderThan > 0 {
+			if !workflow.CreationTimestamp.Time.Before(cutoff) {
+				continue
+			}
+		} else if workflow.Spec.State.Status == workflowsv1.WorkflowRunning {
+			continue
+		}
+
+		artifact := workflowArtifact(workflow)
+		if !options.DryRun {
+			log.Debugf("Deleting workflow resource %v", artifact.Name)
+			err = kube.DeleteWorkflow(workflowsClient, workflow)
+			if err != nil {
+				log.Errorf("Error deleting workflow resource %v: %v", artifact.Name, err.Error())
+				continue
+			}
+		}
+
+		report.add(&artifact, false)
+	}
+
+	return nil
+}
+
+// Prune Remove the images, containers, services and workflow resources generated by Sandbox which are
+// selected by the options. Containers are removed first, so that the images they were created from can be
+// removed after them. Services and workflow resources are only pruned when Kubernetes clients are given
+func Prune(ctx context.Context, dockerClient *client.Client, clientSet *kubernetes.Clientset,
+	workflowsClient *rest.RESTClient, options *Options) (*Report, error) {
+	report := &Report{}
+
+	err := pruneContainers(ctx, dockerClient, options, report)
+	if err != nil {
+		return nil, err
+	}
+
+	err = pruneImages(ctx, dockerClient, options, report)
+	if err != nil {
+		return nil, err
+	}
+
+	if clientSet != nil {
+		err = pruneServices(clientSet, options, report)
+		if err != nil {
+			return nil, err
+		}
+
+		if !options.DryRun {
+			kube.DeleteOrphanedNamespaces(clientSet, "")
+		}
+	}
+
+	if workflowsClient != nil {
+		err = pruneWorkflows(workflowsClient, options, report)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
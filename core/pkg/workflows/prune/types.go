@@ -0,0 +1,41 @@
+package prune
+
+import (
+	"time"
+)
+
+// ImageArtifact Generated images
+const ImageArtifact = "image"
+
+// ContainerArtifact Exited containers of steps
+const ContainerArtifact = "container"
+
+// ServiceArtifact Services of steps whose pods no longer exist
+const ServiceArtifact = "service"
+
+// WorkflowArtifact Workflow resources mirroring runs which are no longer running
+const WorkflowArtifact = "workflow"
+
+// Options Options which select the generated artifacts to prune
+type Options struct {
+	All       bool
+	DryRun    bool
+	OlderThan time.Duration
+	Project   string
+}
+
+// Artifact An artifact generated by Sandbox which can be pruned
+type Artifact struct {
+	Created    time.Time
+	ID         string
+	Kind       string
+	Name       string
+	Size       int64
+	references []string
+}
+
+// Report The artifacts which were pruned, and the space reclaimed by pruning them
+type Report struct {
+	Artifacts []Artifact
+	Reclaimed int64
+}
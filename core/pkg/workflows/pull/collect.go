@@ -0,0 +1,158 @@
+package pull
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/errors"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/files"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/image"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+// Image An image referenced by the steps of a workflow, along with the policy it is pulled with
+type Image struct {
+	Name   string
+	Policy v1.PullPolicy
+}
+
+type collector struct {
+	dockerfiles bool
+	errors      *errors.CompositeError
+	images      []Image
+	indexes     map[string]int
+	visiting    map[string]bool
+}
+
+func (c *collector) add(name string, policy v1.PullPolicy) {
+	index, ok := c.indexes[name]
+	if !ok {
+		c.indexes[name] = len(c.images)
+		c.images = append(c.images, Image{Name: name, Policy: policy})
+		return
+	}
+
+	// Steps which always pull an image win over steps which don't, and steps which never pull an image only
+	// keep it from being pulled if no other step pulls it
+	existing := &c.images[index]
+	if policy == v1.PullAlways || existing.Policy == v1.PullNever {
+		existing.Policy = policy
+	}
+}
+
+func (c *collector) addStepImage(workflow *v1.Workflow, step *v1.WorkflowStep) {
+	image, err := workflow.Spec.State.Variables.Expand(step.Image())
+	if err != nil {
+		log.Debugf("Not pulling image %v before the run, as it uses variables which aren't known yet", step.Image())
+		return
+	}
+
+	pinned, ok := workflow.Spec.State.Pins[image]
+	if ok {
+		image = pinned
+	}
+
+	c.add(image, step.PullPolicy())
+}
+
+// addDockerfileImages Add the base images of the Dockerfile of a step. Base images which depend on build
+// arguments can't be determined up front
+func (c *collector) addDockerfileImages(workflow *v1.Workflow, step *v1.WorkflowStep) {
+	path := step.Dockerfile()
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workflow.Spec.State.ProjectRoot, path)
+	}
+
+	dockerfile, err := ioutil.ReadFile(path)
+	if err != nil {
+		c.errors.Append(err)
+		return
+	}
+
+	baseImages, err := image.BaseImages(dockerfile)
+	if err != nil {
+		c.errors.Append(err)
+		return
+	}
+
+	for _, baseImage := range baseImages {
+		if strings.Contains(baseImage, "$") {
+			c.errors.Append(fmt.Errorf("The base image %v of Dockerfile %v depends on build arguments, so it can't be bundled",
+				baseImage, step.Dockerfile()))
+			continue
+		}
+
+		c.add(baseImage, step.PullPolicy())
+	}
+}
+
+func (c *collector) addExternalWorkflow(workflow *v1.Workflow, step *v1.WorkflowStep) {
+	name, err := workflow.Spec.State.Variables.Expand(step.External.Workflow)
+	if err != nil || c.visiting[name] {
+		return
+	}
+
+	called, err := files.ReadWorkflow(name)
+	if err != nil {
+		log.Debugf("Unable to read workflow %v to collect its images: %v", name, err.Error())
+		return
+	}
+
+	called.Spec.State.Pins = workflow.Spec.State.Pins
+
+	c.visiting[name] = true
+	c.addSteps(called, called.Spec.Steps)
+	c.visiting[name] = false
+}
+
+func (c *collector) addSteps(workflow *v1.Workflow, steps []v1.WorkflowStep) {
+	for i := range steps {
+		step := &steps[i]
+
+		if step.IsSkipped() {
+			continue
+		}
+
+		if step.Compound != nil {
+			c.addSteps(workflow, step.Compound.Steps)
+		} else if step.External != nil {
+			c.addExternalWorkflow(workflow, step)
+		} else if len(step.Image()) > 0 {
+			c.addStepImage(workflow, step)
+		} else if c.dockerfiles && step.HasDockerfile() {
+			c.addDockerfileImages(workflow, step)
+		}
+	}
+}
+
+// CollectImages Collect the images referenced by the steps of a workflow, including the steps of compound
+// steps and called workflows, in the order they are first referenced. Images are replaced by the digests
+// they are pinned to
+func CollectImages(workflow *v1.Workflow) []Image {
+	c := &collector{
+		errors:   errors.NewCompositeError(),
+		indexes:  make(map[string]int),
+		visiting: map[string]bool{workflow.Name: true},
+	}
+
+	c.addSteps(workflow, workflow.Spec.Steps)
+	return c.images
+}
+
+// CollectAllImages Collect the images referenced by the steps of a workflow, like CollectImages, along with
+// the base images of the Dockerfiles of steps. An error is returned if any of the base images can't be
+// determined, so that images aren't silently left out
+func CollectAllImages(workflow *v1.Workflow) ([]Image, error) {
+	c := &collector{
+		dockerfiles: true,
+		errors:      errors.NewCompositeError(),
+		indexes:     make(map[string]int),
+		visiting:    map[string]bool{workflow.Name: true},
+	}
+
+	c.addSteps(workflow, workflow.Spec.Steps)
+	return c.images, c.errors.OrNilIfEmpty()
+}
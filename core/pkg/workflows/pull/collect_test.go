@@ -0,0 +1,191 @@
+package pull
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+const testWorkflow = `
+variables:
+  - name: version
+    value: "3.6"
+steps:
+  - run:
+      name: build
+      image: golang
+      script: go build
+  - compound:
+      name: checks
+      steps:
+        - run:
+            name: lint
+            image: golang
+            pull: always
+            script: golint
+        - run:
+            name: test
+            image: alpine:${version}
+            script: ./test.sh
+  - run:
+      name: package
+      image: alpine:${tag}
+      script: ./package.sh
+  - external:
+      name: deploy
+      workflow: deploy
+  - run:
+      name: dockerfile
+      dockerfile: Dockerfile
+`
+
+const deployWorkflow = `
+steps:
+  - run:
+      name: upload
+      image: alpine:3.6
+      pull: never
+      script: ./upload.sh
+  - run:
+      name: notify
+      image: curl
+      pull: never
+      script: ./notify.sh
+  - external:
+      name: again
+      workflow: test
+`
+
+func createTestProject(t *testing.T) string {
+	directory, err := ioutil.TempDir("", "sbox-pull")
+	if err != nil {
+		t.Fatalf("Unable to create project directory: %v", err)
+	}
+
+	err = os.Mkdir(filepath.Join(directory, "workflows"), 0755)
+	if err != nil {
+		t.Fatalf("Unable to create workflows directory: %v", err)
+	}
+
+	for name, content := range map[string]string{
+		"Dockerfile":           "FROM golang:1.9 AS build\nFROM scratch\n",
+		"workflows/deploy.yml": deployWorkflow,
+		"workflows/test.yml":   testWorkflow,
+	} {
+		err = ioutil.WriteFile(filepath.Join(directory, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("Unable to write project file %v: %v", name, err)
+		}
+	}
+
+	return directory
+}
+
+func TestCollectImages(t *testing.T) {
+	tests := []struct {
+		name   string
+		pins   map[string]string
+		all    bool
+		images []Image
+	}{
+		{
+			name: "images",
+			images: []Image{
+				{Name: "golang", Policy: v1.PullAlways},
+				{Name: "alpine:3.6", Policy: v1.PullIfNotPresent},
+				{Name: "curl", Policy: v1.PullNever},
+			},
+		},
+		{
+			name: "pinned images",
+			pins: map[string]string{"alpine:3.6": "alpine@sha256:1234"},
+			images: []Image{
+				{Name: "golang", Policy: v1.PullAlways},
+				{Name: "alpine@sha256:1234", Policy: v1.PullIfNotPresent},
+				{Name: "curl", Policy: v1.PullNever},
+			},
+		},
+		{
+			name: "dockerfile base images",
+			all:  true,
+			images: []Image{
+				{Name: "golang", Policy: v1.PullAlways},
+				{Name: "alpine:3.6", Policy: v1.PullIfNotPresent},
+				{Name: "curl", Policy: v1.PullNever},
+				{Name: "golang:1.9", Policy: v1.PullIfNotPresent},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			directory := createTestProject(t)
+			defer os.RemoveAll(directory)
+
+			workingDirectory, err := os.Getwd()
+			if err != nil {
+				t.Fatalf("Unable to get working directory: %v", err)
+			}
+
+			err = os.Chdir(directory)
+			if err != nil {
+				t.Fatalf("Unable to change to project directory: %v", err)
+			}
+			defer os.Chdir(workingDirectory)
+
+			workflow, err := v1.ParseWorkflow(directory, "test", []byte(testWorkflow))
+			if err != nil {
+				t.Fatalf("Unable to parse workflow: %v", err)
+			}
+
+			workflow.Spec.State.Pins = test.pins
+
+			var images []Image
+			if test.all {
+				images, err = CollectAllImages(workflow)
+				if err != nil {
+					t.Fatalf("Unable to collect images: %v", err)
+				}
+			} else {
+				images = CollectImages(workflow)
+			}
+
+			if !reflect.DeepEqual(images, test.images) {
+				t.Errorf("Expected images %v, but they were %v", test.images, images)
+			}
+		})
+	}
+}
+
+func TestCollectAllImagesBuildArguments(t *testing.T) {
+	directory, err := ioutil.TempDir("", "sbox-pull")
+	if err != nil {
+		t.Fatalf("Unable to create project directory: %v", err)
+	}
+	defer os.RemoveAll(directory)
+
+	err = ioutil.WriteFile(filepath.Join(directory, "Dockerfile"), []byte("ARG VERSION\nFROM alpine:${VERSION}\n"),
+		0644)
+	if err != nil {
+		t.Fatalf("Unable to write Dockerfile: %v", err)
+	}
+
+	workflow, err := v1.ParseWorkflow(directory, "test", []byte(`
+steps:
+  - run:
+      name: build
+      dockerfile: Dockerfile
+`))
+	if err != nil {
+		t.Fatalf("Unable to parse workflow: %v", err)
+	}
+
+	images, err := CollectAllImages(workflow)
+	if err == nil {
+		t.Errorf("Expected an error for a base image depending on build arguments, but got images %v", images)
+	}
+}
@@ -0,0 +1,123 @@
+package pull
+
+import (
+	"context"
+	"strings"
+
+	"github.com/docker/engine-api/client"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/docker"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/expansion"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+func pullRequests(workflow *v1.Workflow, images []Image, policy v1.PullPolicy) ([]docker.PullRequest, error) {
+	registries, err := expansion.ExpandRegistries(workflow.Spec.Registries, workflow.Spec.State.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []docker.PullRequest
+	for _, image := range images {
+		if image.Policy == v1.PullNever {
+			continue
+		}
+
+		registryAuth, err := docker.RegistryAuth(image.Name, registries)
+		if err != nil {
+			return nil, err
+		}
+
+		request := docker.PullRequest{
+			Image:        image.Name,
+			Policy:       image.Policy,
+			RegistryAuth: registryAuth,
+		}
+
+		if len(policy) > 0 {
+			request.Policy = policy
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+// PrePull Pull the images referenced by the steps of a workflow before it runs, as required by the pull
+// policies of the steps, so that the images are pulled at the same time rather than one by one as each
+// step is built
+func PrePull(ctx context.Context, dockerClient *client.Client, workflow *v1.Workflow) error {
+	requests, err := pullRequests(workflow, CollectImages(workflow), "")
+	if err != nil {
+		return err
+	}
+
+	return docker.PullImages(ctx, dockerClient, requests)
+}
+
+// Lock Pull the latest versions of the images referenced by the steps of a workflow, and resolve each of
+// them to the digest it was pulled at. Images which are already referenced by digest, or which weren't
+// pulled from a registry, are left out
+func Lock(ctx context.Context, dockerClient *client.Client, workflow *v1.Workflow) (map[string]string, error) {
+	workflow.Spec.State.Pins = nil
+	images := CollectImages(workflow)
+
+	requests, err := pullRequests(workflow, images, v1.PullAlways)
+	if err != nil {
+		return nil, err
+	}
+
+	err = docker.PullImages(ctx, dockerClient, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	pins := make(map[string]string)
+	for _, image := range images {
+		if strings.Contains(image.Name, "@") {
+			continue
+		}
+
+		digest, err := docker.ImageDigest(ctx, dockerClient, image.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(digest) < 1 {
+			log.Infof("Image %v is not present or was not pulled from a registry, so it can't be pinned", image.Name)
+			continue
+		}
+
+		pins[image.Name] = digest
+	}
+
+	return pins, nil
+}
+
+// Fetch Pull the images referenced by the steps of a workflow, including the base images of their
+// Dockerfiles, which aren't present yet, and get the names of all the images
+func Fetch(ctx context.Context, dockerClient *client.Client, workflow *v1.Workflow) ([]string, error) {
+	images, err := CollectAllImages(workflow)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := pullRequests(workflow, images, v1.PullIfNotPresent)
+	if err != nil {
+		return nil, err
+	}
+
+	err = docker.PullImages(ctx, dockerClient, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(images))
+	for _, image := range images {
+		names = append(names, image.Name)
+	}
+
+	return names, nil
+}
@@ -0,0 +1,109 @@
+package report
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"time"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+func (r *Reporter) testCase(workflow *v1.Workflow, step *v1.WorkflowStep, selector []int, className string) (*junitTestCase, time.Duration) {
+	duration := stepDuration(workflow, step)
+
+	testCase := &junitTestCase{
+		Name:      step.StepName(selector),
+		ClassName: className,
+		Time:      formatSeconds(duration),
+	}
+
+	output := r.output(workflow, selector)
+
+	switch step.State.Status {
+	case v1.StepSucceeded:
+	case v1.StepFailed:
+		testCase.Failure = &junitFailure{Message: "Step failed", Content: output}
+	case v1.StepRunning, v1.StepCancelled:
+		testCase.Skipped = &junitSkipped{Message: "Step was cancelled before it finished"}
+		testCase.SystemOut = output
+	case v1.StepFailureIgnored:
+		testCase.Skipped = &junitSkipped{Message: "Step failed, but the failure was ignored"}
+		testCase.SystemOut = output
+	case v1.StepSkipped:
+		testCase.Skipped = &junitSkipped{Message: "Step was skipped"}
+	default:
+		testCase.Skipped = &junitSkipped{Message: "Step did not run"}
+	}
+
+	return testCase, duration
+}
+
+func (s *junitTestSuite) addCase(testCase *junitTestCase) {
+	s.Cases = append(s.Cases, testCase)
+	s.Tests++
+
+	if testCase.Failure != nil {
+		s.Failures++
+	} else if testCase.Skipped != nil {
+		s.Skipped++
+	}
+}
+
+func (s *junitTestSuite) addSuite(suite *junitTestSuite) {
+	s.Suites = append(s.Suites, suite)
+	s.Tests += suite.Tests
+	s.Failures += suite.Failures
+	s.Skipped += suite.Skipped
+}
+
+func (r *Reporter) testSuite(workflow *v1.Workflow, name string, steps []v1.WorkflowStep, parent []int) (*junitTestSuite, time.Duration) {
+	suite := &junitTestSuite{Name: name}
+
+	var total time.Duration
+	for i := range steps {
+		step := &steps[i]
+		selector := append(append([]int{}, parent...), i)
+
+		if step.Compound != nil {
+			compound, duration := r.testSuite(workflow, name+"."+step.StepName(selector), step.Compound.Steps, selector)
+			suite.addSuite(compound)
+			total += duration
+		} else {
+			testCase, duration := r.testCase(workflow, step, selector, name)
+			suite.addCase(testCase)
+			total += duration
+		}
+	}
+
+	suite.Time = formatSeconds(total)
+	return suite, total
+}
+
+func (r *Reporter) workflowSuite(workflow *v1.Workflow) *junitTestSuite {
+	suite, _ := r.testSuite(workflow, workflow.Name, workflow.Spec.Steps, nil)
+	suite.Time = formatSeconds(workflowDuration(workflow))
+	return suite
+}
+
+func (r *Reporter) writeJUnit(file string) error {
+	suites := &junitTestSuites{
+		Name: r.root.Name,
+		Time: formatSeconds(workflowDuration(r.root)),
+	}
+
+	for _, workflow := range append([]*v1.Workflow{r.root}, r.children...) {
+		suite := r.workflowSuite(workflow)
+
+		suites.Suites = append(suites.Suites, suite)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+	}
+
+	content, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, []byte(xml.Header+string(content)+"\n"), 0644)
+}
@@ -0,0 +1,229 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	executioncontext "github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testWorkflow = `
+steps:
+  - run:
+      name: build
+      image: alpine
+      script: make
+  - compound:
+      name: checks
+      steps:
+        - run:
+            name: test
+            image: alpine
+            script: make test
+        - run:
+            name: lint
+            image: alpine
+            script: make lint
+  - run:
+      name: deploy
+      image: alpine
+      script: make deploy
+  - service:
+      name: db
+      image: postgres
+  - run:
+      name: cleanup
+      image: alpine
+      script: make clean
+`
+
+const failureOutput = `expected <1> & got "2"`
+
+func finishTestStep(t *testing.T, reporter *Reporter, workflow *v1.Workflow, selector []int,
+	status v1.StepStatus, output string) {
+	step := workflow.Select(selector)
+
+	started := metav1.NewTime(workflow.Spec.State.Started.Add(time.Second))
+	finished := metav1.NewTime(started.Add(2 * time.Second))
+
+	step.State.Status = status
+	step.State.Started = &started
+	step.State.Finished = &finished
+
+	if len(output) > 0 {
+		_, err := io.WriteString(reporter.StepOutput(workflow, selector, executioncontext.RunOutput), output+"\n")
+		if err != nil {
+			t.Fatalf("Unable to write output of step: %v", err)
+		}
+	}
+}
+
+func writeTestJUnit(t *testing.T) (string, *junitTestSuites) {
+	workflow, err := v1.ParseWorkflow("/project", "test", []byte(testWorkflow))
+	if err != nil {
+		t.Fatalf("Unable to parse workflow: %v", err)
+	}
+
+	started := metav1.NewTime(time.Date(2017, 10, 1, 12, 0, 0, 0, time.UTC))
+	finished := metav1.NewTime(started.Add(10 * time.Second))
+	workflow.Spec.State.Started = &started
+	workflow.Spec.State.Finished = &finished
+
+	directory, err := ioutil.TempDir("", "sbox-report")
+	if err != nil {
+		t.Fatalf("Unable to create report directory: %v", err)
+	}
+	defer os.RemoveAll(directory)
+
+	file := filepath.Join(directory, "report.xml")
+	reporter, err := NewReporter([]string{"junit=" + file})
+	if err != nil {
+		t.Fatalf("Unable to create reporter: %v", err)
+	}
+
+	reporter.WorkflowStarted(workflow)
+
+	finishTestStep(t, reporter, workflow, []int{0}, v1.StepSucceeded, "built")
+	finishTestStep(t, reporter, workflow, []int{1, 0}, v1.StepFailed, failureOutput)
+	finishTestStep(t, reporter, workflow, []int{1, 1}, v1.StepFailureIgnored, "warning")
+	finishTestStep(t, reporter, workflow, []int{2}, v1.StepSkipped, "")
+	finishTestStep(t, reporter, workflow, []int{3}, v1.StepRunning, "listening")
+
+	reporter.WorkflowFinished(workflow)
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Unable to read JUnit report: %v", err)
+	}
+
+	var suites junitTestSuites
+	err = xml.Unmarshal(content, &suites)
+	if err != nil {
+		t.Fatalf("Unable to parse JUnit report: %v\n%v", err, string(content))
+	}
+
+	return string(content), &suites
+}
+
+func TestJUnitEscaping(t *testing.T) {
+	content, suites := writeTestJUnit(t)
+
+	if strings.Contains(content, failureOutput) {
+		t.Errorf("Expected the output of the failed step to be escaped, but it wasn't:\n%v", content)
+	}
+
+	failure := suites.Suites[0].Suites[0].Cases[0].Failure
+	if failure == nil || failure.Content != failureOutput {
+		t.Errorf("Expected failure with output %v, but it was %v", failureOutput, failure)
+	}
+}
+
+func TestJUnitStatuses(t *testing.T) {
+	_, suites := writeTestJUnit(t)
+
+	if suites.Tests != 6 || suites.Failures != 1 || suites.Skipped != 4 {
+		t.Errorf("Expected 6 tests, 1 failure and 4 skipped, but there were %v tests, %v failures and %v skipped",
+			suites.Tests, suites.Failures, suites.Skipped)
+	}
+
+	if len(suites.Suites) != 1 || len(suites.Suites[0].Suites) != 1 {
+		t.Fatalf("Expected a suite for the workflow containing a suite for the compound step, but there were %v",
+			suites.Suites)
+	}
+
+	if name := suites.Suites[0].Suites[0].Name; name != "test.checks" {
+		t.Errorf("Expected suite for the compound step to be named test.checks, but it was %v", name)
+	}
+
+	cases := make(map[string]*junitTestCase)
+	for _, suite := range []*junitTestSuite{suites.Suites[0], suites.Suites[0].Suites[0]} {
+		for _, testCase := range suite.Cases {
+			cases[testCase.Name] = testCase
+		}
+	}
+
+	tests := []struct {
+		name      string
+		className string
+		failure   string
+		skipped   string
+		systemOut string
+	}{
+		{
+			name:      "build",
+			className: "test",
+		},
+		{
+			name:      "test",
+			className: "test.checks",
+			failure:   "Step failed",
+		},
+		{
+			name:      "lint",
+			className: "test.checks",
+			skipped:   "Step failed, but the failure was ignored",
+			systemOut: "warning",
+		},
+		{
+			name:      "deploy",
+			className: "test",
+			skipped:   "Step was skipped",
+		},
+		{
+			name:      "db",
+			className: "test",
+			skipped:   "Step was cancelled before it finished",
+			systemOut: "listening",
+		},
+		{
+			name:      "cleanup",
+			className: "test",
+			skipped:   "Step did not run",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testCase, ok := cases[test.name]
+			if !ok {
+				t.Fatalf("Expected a test case for step %v, but there was none", test.name)
+			}
+
+			if testCase.ClassName != test.className {
+				t.Errorf("Expected class name %v, but it was %v", test.className, testCase.ClassName)
+			}
+
+			if len(test.failure) > 0 {
+				if testCase.Failure == nil || testCase.Failure.Message != test.failure {
+					t.Errorf("Expected failure %v, but it was %v", test.failure, testCase.Failure)
+				}
+			} else if testCase.Failure != nil {
+				t.Errorf("Expected no failure, but it was %v", testCase.Failure)
+			}
+
+			if len(test.skipped) > 0 {
+				if testCase.Skipped == nil || testCase.Skipped.Message != test.skipped {
+					t.Errorf("Expected skipped %v, but it was %v", test.skipped, testCase.Skipped)
+				}
+			} else if testCase.Skipped != nil {
+				t.Errorf("Expected the step not to be skipped, but it was %v", testCase.Skipped)
+			}
+
+			if testCase.SystemOut != test.systemOut {
+				t.Errorf("Expected output %v, but it was %v", test.systemOut, testCase.SystemOut)
+			}
+
+			if testCase.Time != "2.000" && test.name != "cleanup" {
+				t.Errorf("Expected time 2.000, but it was %v", testCase.Time)
+			}
+		})
+	}
+}
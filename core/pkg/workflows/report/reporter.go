@@ -0,0 +1,122 @@
+package report
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/buffer"
+	executioncontext "github.com/stackfoundation/sandbox/core/pkg/workflows/execution/context"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+const summaryReport = "summary"
+const junitReport = "junit="
+
+// NewReporter Create a reporter which produces the specified reports once a workflow finishes. Each report
+// is either "summary", or "junit=<file>"
+func NewReporter(reports []string) (*Reporter, error) {
+	reporter := &Reporter{
+		tails: make(map[string]*outputTail),
+	}
+
+	for _, report := range reports {
+		if report == summaryReport {
+			reporter.summary = true
+		} else if strings.HasPrefix(report, junitReport) && len(report) > len(junitReport) {
+			reporter.junit = report[len(junitReport):]
+		} else {
+			return nil, errors.New(`Unknown report "` + report + `", must be one of: summary, junit=<file>`)
+		}
+	}
+
+	return reporter, nil
+}
+
+// OutputFiles Get the files which the specified reports are written to
+func OutputFiles(reports []string) []string {
+	var files []string
+	for _, report := range reports {
+		if strings.HasPrefix(report, junitReport) && len(report) > len(junitReport) {
+			files = append(files, report[len(junitReport):])
+		}
+	}
+
+	return files
+}
+
+func tailKey(workflow *v1.Workflow, selector []int) string {
+	return workflow.Spec.State.ID + "/" + workflow.StepPath(selector)
+}
+
+func (r *Reporter) tail(workflow *v1.Workflow, selector []int) *outputTail {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := tailKey(workflow, selector)
+	tail, ok := r.tails[key]
+	if !ok {
+		tail = &outputTail{}
+		r.tails[key] = tail
+	}
+
+	return tail
+}
+
+func (r *Reporter) output(workflow *v1.Workflow, selector []int) string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	tail, ok := r.tails[tailKey(workflow, selector)]
+	if !ok {
+		return ""
+	}
+
+	return tail.String()
+}
+
+// WorkflowStarted Keep track of a started workflow, to report on it later
+func (r *Reporter) WorkflowStarted(workflow *v1.Workflow) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.root == nil {
+		r.root = workflow
+	} else {
+		r.children = append(r.children, workflow)
+	}
+}
+
+// ChangeRaised Changes are not reported on
+func (r *Reporter) ChangeRaised(workflow *v1.Workflow, change *v1.Change) {
+}
+
+// StepOutput Get a writer which keeps the tail of the output of a step, to include in reports
+func (r *Reporter) StepOutput(workflow *v1.Workflow, selector []int, stream executioncontext.OutputStream) io.Writer {
+	tail := r.tail(workflow, selector)
+
+	if stream == executioncontext.BuildOutput {
+		return buffer.NewLineBuffer(tail.addBuildMessage, buffer.Unlimited)
+	}
+
+	return buffer.NewLineBuffer(tail.addOutputLine, buffer.Unlimited)
+}
+
+// WorkflowFinished Produce reports once the workflow that was run finishes
+func (r *Reporter) WorkflowFinished(workflow *v1.Workflow) {
+	if workflow != r.root {
+		return
+	}
+
+	if r.summary {
+		r.writeSummary(log.Output())
+	}
+
+	if len(r.junit) > 0 {
+		err := r.writeJUnit(r.junit)
+		if err != nil {
+			log.Errorf("Error writing JUnit report to %v: %v", r.junit, err.Error())
+		}
+	}
+}
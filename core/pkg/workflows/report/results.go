@@ -0,0 +1,56 @@
+package report
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+func stepDuration(workflow *v1.Workflow, step *v1.WorkflowStep) time.Duration {
+	started := step.State.Started
+	if started == nil {
+		return 0
+	}
+
+	finished := step.State.Finished
+	if finished == nil {
+		finished = workflow.Spec.State.Finished
+	}
+
+	if finished == nil {
+		return 0
+	}
+
+	return finished.Sub(started.Time)
+}
+
+func workflowDuration(workflow *v1.Workflow) time.Duration {
+	state := &workflow.Spec.State
+	if state.Started == nil || state.Finished == nil {
+		return 0
+	}
+
+	return state.Finished.Sub(state.Started.Time)
+}
+
+func formatSeconds(duration time.Duration) string {
+	return strconv.FormatFloat(duration.Seconds(), 'f', 3, 64)
+}
+
+func describeStatus(status v1.StepStatus) string {
+	switch status {
+	case v1.StepSucceeded:
+		return "passed"
+	case v1.StepFailed:
+		return "failed"
+	case v1.StepFailureIgnored:
+		return "ignored-failure"
+	case v1.StepSkipped:
+		return "skipped"
+	case v1.StepRunning, v1.StepCancelled:
+		return "cancelled"
+	}
+
+	return "not run"
+}
@@ -0,0 +1,50 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+func formatDuration(duration time.Duration) string {
+	if duration <= 0 {
+		return "-"
+	}
+
+	return (duration / time.Millisecond * time.Millisecond).String()
+}
+
+func writeStepSummaries(writer io.Writer, workflow *v1.Workflow, prefix string, steps []v1.WorkflowStep, parent []int) {
+	for i := range steps {
+		step := &steps[i]
+		selector := append(append([]int{}, parent...), i)
+
+		if step.Compound != nil {
+			writeStepSummaries(writer, workflow, prefix, step.Compound.Steps, selector)
+		} else {
+			fmt.Fprintf(writer, "%v%v\t%v\t%v\n",
+				prefix,
+				workflow.StepPath(selector),
+				describeStatus(step.State.Status),
+				formatDuration(stepDuration(workflow, step)))
+		}
+	}
+}
+
+func (r *Reporter) writeSummary(writer io.Writer) {
+	table := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "STEP\tSTATUS\tDURATION")
+
+	writeStepSummaries(table, r.root, "", r.root.Spec.Steps, nil)
+	for _, child := range r.children {
+		writeStepSummaries(table, child, child.Name+": ", child.Spec.Steps, nil)
+	}
+
+	table.Flush()
+
+	fmt.Fprintf(writer, "Workflow %v %v in %v\n",
+		r.root.Name, r.root.Spec.State.Status, formatDuration(workflowDuration(r.root)))
+}
@@ -0,0 +1,45 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+func (t *outputTail) add(line string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.lines = append(t.lines, line)
+	if len(t.lines) > maxTailLines {
+		t.lines = t.lines[len(t.lines)-maxTailLines:]
+	}
+}
+
+func (t *outputTail) addOutputLine(line []byte) {
+	t.add(strings.TrimRight(string(line), "\r"))
+}
+
+func (t *outputTail) addBuildMessage(line []byte) {
+	var message jsonmessage.JSONMessage
+	err := json.Unmarshal(line, &message)
+	if err != nil {
+		return
+	}
+
+	if len(message.Stream) > 0 {
+		t.add(strings.TrimRight(message.Stream, "\r\n"))
+	}
+
+	if message.Error != nil {
+		t.add(message.Error.Message)
+	}
+}
+
+func (t *outputTail) String() string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return strings.Join(t.lines, "\n")
+}
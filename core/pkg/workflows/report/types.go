@@ -0,0 +1,63 @@
+package report
+
+import (
+	"encoding/xml"
+	"sync"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+const maxTailLines = 50
+
+type outputTail struct {
+	lines []string
+	lock  sync.Mutex
+}
+
+// Reporter Reports on the results of a workflow run, once it finishes
+type Reporter struct {
+	children []*v1.Workflow
+	junit    string
+	lock     sync.Mutex
+	root     *v1.Workflow
+	summary  bool
+	tails    map[string]*outputTail
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitTestSuite struct {
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite,omitempty"`
+	Cases    []*junitTestCase  `xml:"testcase,omitempty"`
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
@@ -0,0 +1,208 @@
+package resume
+
+import (
+	"context"
+	"errors"
+
+	"github.com/docker/engine-api/client"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/docker"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/history"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+	"github.com/stackfoundation/sandbox/log"
+)
+
+func sameSelector(selector []int, other []int) bool {
+	if len(selector) != len(other) {
+		return false
+	}
+
+	for i := range selector {
+		if selector[i] != other[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func collectSteps(workflow *v1.Workflow) map[string]*v1.WorkflowStep {
+	steps := make(map[string]*v1.WorkflowStep)
+
+	selector := workflow.IncrementStepSelector([]int{})
+	for len(selector) > 0 {
+		steps[workflow.StepPath(selector)] = workflow.Select(selector)
+		selector = workflow.IncrementStepSelector(selector)
+	}
+
+	return steps
+}
+
+func collectReferencedSteps(workflow *v1.Workflow) map[string]bool {
+	referenced := make(map[string]bool)
+
+	selector := workflow.IncrementStepSelector([]int{})
+	for len(selector) > 0 {
+		step := workflow.Select(selector)
+		if step.UsesPreviousStep() {
+			referenced[step.Step()] = true
+		}
+
+		for _, pick := range step.CherryPick() {
+			referenced[pick.Step] = true
+		}
+
+		selector = workflow.IncrementStepSelector(selector)
+	}
+
+	return referenced
+}
+
+func hasCompleted(step *v1.WorkflowStep) bool {
+	return step.IsComplete() && step.State.Finished != nil
+}
+
+func firstIncompleteStep(workflow *v1.Workflow) string {
+	selector := workflow.IncrementStepSelector([]int{})
+	for len(selector) > 0 {
+		step := workflow.Select(selector)
+		if step.Service == nil && !hasCompleted(step) {
+			return workflow.StepPath(selector)
+		}
+
+		selector = workflow.IncrementStepSelector(selector)
+	}
+
+	return ""
+}
+
+func checkRestorable(dockerClient *client.Client, previous *v1.WorkflowStep, referenced bool) error {
+	if previous == nil {
+		return errors.New("it did not run previously")
+	}
+
+	if !hasCompleted(previous) {
+		return errors.New("it did not complete previously")
+	}
+
+	ctx := context.Background()
+
+	image := previous.State.GeneratedImage
+	if len(image) > 0 {
+		exists, err := docker.ImageExists(ctx, dockerClient, image)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return errors.New("its image " + image + " no longer exists")
+		}
+	}
+
+	container := previous.State.GeneratedContainer
+	if referenced && len(container) > 0 {
+		exists, err := docker.ContainerExists(ctx, dockerClient, container)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return errors.New("its container " + container + " no longer exists")
+		}
+	}
+
+	return nil
+}
+
+// restoreStep Restore the state of a step from a previous run, along with the variables it set. Only the
+// variables of restored steps are restored, so that steps which are run again don't start with the values
+// they set in the previous run
+func restoreStep(workflow *v1.Workflow, step *v1.WorkflowStep, previous *v1.WorkflowStep) {
+	workflow.Spec.State.Variables.Merge(v1.CollectVariables(previous.State.Variables))
+
+	step.State = previous.State
+	step.State.Status = v1.StepSkipped
+	step.State.Prepared = true
+	step.State.Ready = true
+	step.State.Done = true
+}
+
+// Resume Restore the state of all steps in a workflow which precede the specified step, using the state
+// recorded in a previous run. Steps whose images are gone are not restored, and are run again instead. If
+// no step is specified, the workflow resumes from the first step that did not complete in the previous run
+func Resume(dockerClient *client.Client, workflow *v1.Workflow, run *history.Run, from string) error {
+	previous := &v1.Workflow{Spec: run.Spec}
+
+	if len(from) == 0 {
+		from = firstIncompleteStep(previous)
+		if len(from) == 0 {
+			return errors.New("Run " + run.ID + " of workflow " + workflow.Name + " completed, there is nothing to resume")
+		}
+	}
+
+	fromSelector := workflow.FindStep(from)
+	if fromSelector == nil {
+		return errors.New(`No step named "` + from + `" in workflow ` + workflow.Name)
+	}
+
+	previousSteps := collectSteps(previous)
+	referencedSteps := collectReferencedSteps(workflow)
+
+	restored := 0
+	selector := workflow.IncrementStepSelector([]int{})
+	for len(selector) > 0 && !sameSelector(selector, fromSelector) {
+		step := workflow.Select(selector)
+		if step.Service == nil {
+			path := workflow.StepPath(selector)
+			stepName := step.StepName(selector)
+
+			err := checkRestorable(dockerClient, previousSteps[path], referencedSteps[stepName])
+			if err != nil {
+				log.Infof("Step %v cannot be reused because %v, it will be run again", stepName, err.Error())
+				break
+			}
+
+			restoreStep(workflow, step, previousSteps[path])
+			restored++
+		}
+
+		selector = workflow.IncrementStepSelector(selector)
+	}
+
+	log.Infof("Resuming workflow %v from run %v, reusing %v completed steps", workflow.Name, run.ID, restored)
+	return nil
+}
+
+func wasRunning(previous *v1.WorkflowStep) bool {
+	return previous != nil && previous.State.Started != nil && previous.State.Finished == nil &&
+		len(previous.State.GeneratedImage) > 0
+}
+
+// Reattach Mark the steps of a workflow which were still running when a previous run stopped, and which weren't
+// restored, to be reattached to. Instead of building its image and running it again, a step which is reattached
+// to follows the pod it was running in, if the pod still exists. The step is prepared again, so that it can still
+// be run again with the variables of the resumed workflow if its pod is gone
+func Reattach(workflow *v1.Workflow, run *history.Run) {
+	previousSteps := collectSteps(&v1.Workflow{Spec: run.Spec})
+
+	selector := workflow.IncrementStepSelector([]int{})
+	for len(selector) > 0 {
+		step := workflow.Select(selector)
+		previous := previousSteps[workflow.StepPath(selector)]
+
+		if step.RequiresBuild() && !step.State.Done && wasRunning(previous) {
+			step.State = previous.State
+			step.State.GeneratedContainer = ""
+			step.State.Status = v1.StepRunning
+			step.State.Prepared = false
+			step.State.Ready = false
+			step.State.Done = false
+			step.State.Reattach = true
+			step.State.Variables = nil
+
+			log.Debugf("Step %v was still running in run %v, it will be reattached to", step.StepName(selector), run.ID)
+		}
+
+		selector = workflow.IncrementStepSelector(selector)
+	}
+}
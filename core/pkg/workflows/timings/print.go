@@ -0,0 +1,84 @@
+package timings
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/go-units"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+func between(start *metav1.Time, end *metav1.Time) string {
+	if start == nil || end == nil {
+		return "-"
+	}
+
+	duration := end.Sub(start.Time)
+	if duration < 0 {
+		duration = 0
+	}
+
+	return (duration / time.Millisecond * time.Millisecond).String()
+}
+
+func contextSize(timings *v1.StepTimings) string {
+	if timings.ContextStarted == nil {
+		return "-"
+	}
+
+	return units.HumanSize(float64(timings.ContextSize))
+}
+
+func contextFiles(timings *v1.StepTimings) string {
+	if timings.ContextStarted == nil {
+		return "-"
+	}
+
+	return fmt.Sprintf("%v", timings.ContextFiles)
+}
+
+func printStepTimings(writer io.Writer, workflow *v1.Workflow, steps []v1.WorkflowStep, parent []int) {
+	for i := range steps {
+		step := &steps[i]
+		selector := append(append([]int{}, parent...), i)
+
+		if step.Compound != nil {
+			printStepTimings(writer, workflow, step.Compound.Steps, selector)
+			continue
+		}
+
+		if step.State.Started == nil {
+			continue
+		}
+
+		state := &step.State
+		timings := &state.Timings
+
+		fmt.Fprintf(writer, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
+			workflow.StepPath(selector),
+			between(state.Started, timings.Prepared),
+			between(timings.ContextStarted, timings.ContextFinished),
+			between(timings.ContextFinished, timings.BuildFinished),
+			between(timings.PodRequested, timings.PodCreated),
+			between(timings.PodCreated, timings.ContainerStarted),
+			between(timings.ContainerStarted, timings.Ready),
+			between(timings.ContainerStarted, state.Finished),
+			between(state.Started, state.Finished),
+			contextFiles(timings),
+			contextSize(timings))
+	}
+}
+
+// Print Print a breakdown of the time spent in each phase of building and running the steps of a workflow
+func Print(writer io.Writer, workflow *v1.Workflow) {
+	table := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "STEP\tPREPARE\tCONTEXT\tBUILD\tPOD CREATE\tCONTAINER START\tREADY\tDONE\tTOTAL\tFILES\tCONTEXT SIZE")
+
+	printStepTimings(table, workflow, workflow.Spec.Steps, nil)
+
+	table.Flush()
+}
@@ -2,6 +2,26 @@ package v1
 
 import "strconv"
 
+// Build Get the options for customising the Dockerfile generated for this step, if it has any
+func (s *WorkflowStep) Build() *BuildOptions {
+	scriptOptions := s.scriptStepOptions()
+	if scriptOptions != nil {
+		return &scriptOptions.Build
+	}
+
+	return nil
+}
+
+// BuildArgs Get the build arguments for the image build of this step, if it has any
+func (s *WorkflowStep) BuildArgs() []VariableSource {
+	scriptOptions := s.scriptStepOptions()
+	if scriptOptions != nil {
+		return scriptOptions.BuildArgs
+	}
+
+	return nil
+}
+
 // CherryPick Get the cherry picked files for this step, if it has any
 func (s *WorkflowStep) CherryPick() []CherryPick {
 	// scriptOptions := s.scriptStepOptions()
@@ -42,6 +62,16 @@ func (s *WorkflowStep) Image() string {
 	return ""
 }
 
+// Labels Get the labels to put on the image of this step, if it has any
+func (s *WorkflowStep) Labels() []VariableSource {
+	scriptOptions := s.scriptStepOptions()
+	if scriptOptions != nil {
+		return scriptOptions.Labels
+	}
+
+	return nil
+}
+
 // Name Get the name of the step, if it has one
 func (s *WorkflowStep) Name() string {
 	options := s.StepOptions()
@@ -62,6 +92,19 @@ func (s *WorkflowStep) Script() string {
 	return ""
 }
 
+// PublishOptions Get the options for publishing the image of this step, if it is published
+func (s *WorkflowStep) PublishOptions() *PublishOptions {
+	if s.Run != nil {
+		return s.Run.Publish
+	} else if s.Service != nil {
+		return s.Service.Publish
+	} else if s.Publish != nil {
+		return &s.Publish.PublishOptions
+	}
+
+	return nil
+}
+
 func (s *WorkflowStep) scriptStepOptions() *ScriptStepOptions {
 	if s.Run != nil {
 		return &s.Run.ScriptStepOptions
@@ -74,6 +117,14 @@ func (s *WorkflowStep) scriptStepOptions() *ScriptStepOptions {
 	return nil
 }
 
+// SetImage Set the image of this step
+func (s *WorkflowStep) SetImage(image string) {
+	scriptOptions := s.scriptStepOptions()
+	if scriptOptions != nil {
+		scriptOptions.Image = image
+	}
+}
+
 // SetVolumes Set the volumes for this step
 func (s *WorkflowStep) SetVolumes(volumes []Volume) {
 	scriptOptions := s.scriptStepOptions()
@@ -133,11 +184,23 @@ func (s *WorkflowStep) StepOptions() *StepOptions {
 		return &s.Compound.StepOptions
 	} else if s.Generator != nil {
 		return &s.Generator.StepOptions
+	} else if s.Publish != nil {
+		return &s.Publish.StepOptions
 	}
 
 	return nil
 }
 
+// Target Get the stage of the Dockerfile of this step to build, if one is specified
+func (s *WorkflowStep) Target() string {
+	scriptOptions := s.scriptStepOptions()
+	if scriptOptions != nil {
+		return scriptOptions.Target
+	}
+
+	return ""
+}
+
 // Volumes Get the volumes for this step, if it has any
 func (s *WorkflowStep) Volumes() []Volume {
 	scriptOptions := s.scriptStepOptions()
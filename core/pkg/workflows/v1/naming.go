@@ -22,6 +22,12 @@ func GenerateImageName() string {
 	return "step:" + uuid.String()
 }
 
+// GenerateCachedImageName Generates a name for a step image which can be reused by any build with the
+// same cache key
+func GenerateCachedImageName(key string) string {
+	return "step:" + key
+}
+
 // GenerateCachedScriptName Generates a name for a cached step script
 func GenerateCachedScriptName(content string) string {
 	hash := md5.New()
@@ -29,12 +35,6 @@ func GenerateCachedScriptName(content string) string {
 	return "script-" + hex.EncodeToString(hash.Sum(nil)) + ".sh"
 }
 
-// GenerateScriptName Generates a name for a step script
-func GenerateScriptName() string {
-	uuid := uuid.NewUUID()
-	return "script-" + uuid.String()[:8] + ".sh"
-}
-
 // GeneratePodName Generates a name for a pod
 func GeneratePodName() string {
 	uuid := uuid.NewUUID()
@@ -53,6 +53,12 @@ func GenerateServiceAssociation() string {
 	return "assoc-" + uuid.String()[:8]
 }
 
+// GenerateNetworkName Generates a name for a container network
+func GenerateNetworkName() string {
+	uuid := uuid.NewUUID()
+	return "sbox-net-" + uuid.String()[:8]
+}
+
 // GenerateVolumeName Generates a name for a step volume
 func GenerateVolumeName() string {
 	uuid := uuid.NewUUID()
@@ -107,6 +113,8 @@ func (s *WorkflowStep) StepName(selector []int) string {
 		options = &s.Service.StepOptions
 	} else if s.Compound != nil {
 		options = &s.Compound.StepOptions
+	} else if s.Publish != nil {
+		options = &s.Publish.StepOptions
 	}
 
 	return options.StepName(selector)
@@ -2,6 +2,25 @@ package v1
 
 import "strconv"
 
+// Type Get the type of the step: run, service, generator, external or publish. Compound steps don't
+// have a type
+func (s *WorkflowStep) Type() string {
+	switch {
+	case s.Run != nil:
+		return "run"
+	case s.Service != nil:
+		return "service"
+	case s.Generator != nil:
+		return "generator"
+	case s.External != nil:
+		return "external"
+	case s.Publish != nil:
+		return "publish"
+	}
+
+	return ""
+}
+
 // HasScript Does step have a dockerfile?
 func (s *WorkflowStep) HasDockerfile() bool {
 	return len(s.Dockerfile()) > 0
@@ -27,6 +46,36 @@ func (s *WorkflowStep) Cached() bool {
 	return false
 }
 
+// NoCache Should the image of the step be built without using the Docker build cache?
+func (s *WorkflowStep) NoCache() bool {
+	scriptOptions := s.scriptStepOptions()
+	if scriptOptions != nil {
+		noCache, _ := strconv.ParseBool(scriptOptions.NoCache)
+		return noCache
+	}
+
+	return false
+}
+
+// PullPolicy Get the policy for pulling the images of the step. The boolean flags accepted by earlier
+// versions map to always and ifNotPresent
+func (s *WorkflowStep) PullPolicy() PullPolicy {
+	scriptOptions := s.scriptStepOptions()
+	if scriptOptions != nil {
+		switch PullPolicy(scriptOptions.Pull) {
+		case PullAlways, PullNever:
+			return PullPolicy(scriptOptions.Pull)
+		}
+
+		pull, _ := strconv.ParseBool(scriptOptions.Pull)
+		if pull {
+			return PullAlways
+		}
+	}
+
+	return PullIfNotPresent
+}
+
 // IgnoreFailure Is ignore failure enabled for this step?
 func (s *WorkflowStep) IgnoreFailure() *bool {
 	options := s.StepOptions()
@@ -60,7 +109,12 @@ func (s *WorkflowStep) IgnoreValidation() *bool {
 // IsAsync Is this an async step (a paralell or service step that skips wait)?
 func (s *WorkflowStep) IsAsync() bool {
 	return (s.Service != nil && s.Service.Readiness != nil && s.Service.Readiness.SkipWait()) ||
-		(s.Run != nil && s.Run.Parallel == "true") ||
+		s.IsParallel()
+}
+
+// IsParallel Is this a step that runs in parallel with the steps after it?
+func (s *WorkflowStep) IsParallel() bool {
+	return (s.Run != nil && s.Run.Parallel == "true") ||
 		(s.External != nil && s.External.Parallel == "true") ||
 		(s.Generator != nil && s.Generator.Parallel == "true")
 }
@@ -85,6 +139,22 @@ func (s *WorkflowStep) RequiresBuild() bool {
 	return s.Service != nil || s.Run != nil || s.Generator != nil
 }
 
+// Pushes Is the published image pushed to a registry?
+func (p *PublishOptions) Pushes() bool {
+	if p != nil {
+		push, _ := strconv.ParseBool(p.Push)
+		return push
+	}
+
+	return false
+}
+
+// IsEmpty Do the specified build options leave the generated Dockerfile as it is?
+func (b *BuildOptions) IsEmpty() bool {
+	return b == nil || (len(b.Environment) < 1 && len(b.Setup) < 1 && len(b.User) < 1 &&
+		len(b.Workdir) < 1)
+}
+
 // OmitsSource Does the specified source options omit source?
 func (s *SourceOptions) OmitsSource() bool {
 	if s != nil {
@@ -94,3 +164,15 @@ func (s *SourceOptions) OmitsSource() bool {
 
 	return false
 }
+
+// IsComplete Has the step completed, either by running or by being skipped?
+func (s *WorkflowStep) IsComplete() bool {
+	return s.State.Status == StepSucceeded ||
+		s.State.Status == StepFailureIgnored ||
+		s.State.Status == StepSkipped
+}
+
+// IsSkipped Is the step to be skipped?
+func (s *WorkflowStep) IsSkipped() bool {
+	return s.State.Status == StepSkipped
+}
@@ -1,5 +1,10 @@
 package v1
 
+import (
+	"bytes"
+	"strings"
+)
+
 // Select Select a workflow step
 func (w *Workflow) Select(selector []int) *WorkflowStep {
 	var step *WorkflowStep
@@ -35,7 +40,11 @@ func (w *Workflow) Parent(selector []int) *WorkflowStep {
 // IncrementStepSelector Increment the given step selector, taking into account compound steps
 func (w *Workflow) IncrementStepSelector(selector []int) []int {
 	if len(selector) == 0 {
-		return []int{0}
+		if len(w.Spec.Steps) == 0 {
+			return []int{}
+		}
+
+		return w.firstStepSelector([]int{0})
 	}
 
 	numSegments := len(selector)
@@ -66,15 +75,68 @@ func (w *Workflow) IncrementStepSelector(selector []int) []int {
 	}
 
 	if len(newSelector) > 0 {
-		for {
-			step := w.Select(newSelector)
-			if step.Compound != nil {
-				newSelector = append(newSelector, 0)
-			} else {
-				break
-			}
-		}
+		return w.firstStepSelector(newSelector)
 	}
 
 	return newSelector
 }
+
+func (w *Workflow) firstStepSelector(selector []int) []int {
+	for {
+		step := w.Select(selector)
+		if step.Compound != nil {
+			selector = append(selector, 0)
+		} else {
+			break
+		}
+	}
+
+	return selector
+}
+
+// StepPath Get the path of a step, made up of the names of the step and all its parents
+func (w *Workflow) StepPath(selector []int) string {
+	var path bytes.Buffer
+
+	steps := w.Spec.Steps
+	for i, segment := range selector {
+		step := &steps[segment]
+
+		if i > 0 {
+			path.WriteString("/")
+		}
+
+		path.WriteString(step.StepName(selector[:i+1]))
+
+		if step.Compound != nil {
+			steps = step.Compound.Steps
+		}
+	}
+
+	return path.String()
+}
+
+// StepMatches Does the specified step match the specified name or path? A step matches the path of any
+// compound step it is part of
+func (w *Workflow) StepMatches(selector []int, path string) bool {
+	stepPath := w.StepPath(selector)
+
+	return stepPath == path ||
+		w.Select(selector).StepName(selector) == path ||
+		strings.HasPrefix(stepPath, path+"/")
+}
+
+// FindStep Find the first step matching the specified name or path. If the path refers to a
+// compound step, the first step within it is found
+func (w *Workflow) FindStep(path string) []int {
+	selector := w.IncrementStepSelector([]int{})
+	for len(selector) > 0 {
+		if w.StepMatches(selector, path) {
+			return selector
+		}
+
+		selector = w.IncrementStepSelector(selector)
+	}
+
+	return nil
+}
@@ -72,17 +72,77 @@ type Pick struct {
 	Copies             []string `json:"copies" yaml:"copies"`
 }
 
+// StepStatus Status of a step
+type StepStatus string
+
+// StepRunning Step is running
+const StepRunning StepStatus = "running"
+
+// StepSucceeded Step completed successfully
+const StepSucceeded StepStatus = "succeeded"
+
+// StepFailed Step failed
+const StepFailed StepStatus = "failed"
+
+// StepFailureIgnored Step failed, but the failure was ignored
+const StepFailureIgnored StepStatus = "failureIgnored"
+
+// StepSkipped Step was not run, because it was skipped
+const StepSkipped StepStatus = "skipped"
+
+// StepCancelled Step was stopped before it completed, because the workflow was cancelled or failed
+const StepCancelled StepStatus = "cancelled"
+
+// PullPolicy Policy for pulling the images a step uses
+type PullPolicy string
+
+// PullAlways Always pull newer versions of the images
+const PullAlways PullPolicy = "always"
+
+// PullIfNotPresent Only pull images which are not present yet
+const PullIfNotPresent PullPolicy = "ifNotPresent"
+
+// PullNever Never pull images, failing the step if they are not present
+const PullNever PullPolicy = "never"
+
+// RegistryCredentials Credentials for a registry which images are pulled from or pushed to
+type RegistryCredentials struct {
+	Password string `json:"password" yaml:"password"`
+	Server   string `json:"server" yaml:"server"`
+	Username string `json:"username" yaml:"username"`
+}
+
 // StepState State of step
 type StepState struct {
-	GeneratedBaseImage string `json:"baseImage" yaml:"baseImage"`
-	GeneratedImage     string `json:"generatedImage" yaml:"generatedImage"`
-	GeneratedContainer string `json:"generatedContainer" yaml:"generatedContainer"`
-	GeneratedScript    string `json:"generatedScript" yaml:"generatedScript"`
-	GeneratedWorkflow  string `json:"generatedWorkflow" yaml:"generatedWorkflow"`
-	Picks              []Pick `json:"picks" yaml:"picks"`
-	Ready              bool   `json:"ready" yaml:"ready"`
-	Done               bool   `json:"done" yaml:"done"`
-	Prepared           bool   `json:"prepared" yaml:"prepared"`
+	GeneratedBaseImage string           `json:"baseImage" yaml:"baseImage"`
+	GeneratedImage     string           `json:"generatedImage" yaml:"generatedImage"`
+	GeneratedContainer string           `json:"generatedContainer" yaml:"generatedContainer"`
+	GeneratedScript    string           `json:"generatedScript" yaml:"generatedScript"`
+	GeneratedWorkflow  string           `json:"generatedWorkflow" yaml:"generatedWorkflow"`
+	Picks              []Pick           `json:"picks" yaml:"picks"`
+	Ready              bool             `json:"ready" yaml:"ready"`
+	Done               bool             `json:"done" yaml:"done"`
+	Prepared           bool             `json:"prepared" yaml:"prepared"`
+	Reattach           bool             `json:"-" yaml:"-"`
+	Status             StepStatus       `json:"status" yaml:"status"`
+	Variables          []VariableSource `json:"variables,omitempty" yaml:"-"`
+	Started            *metav1.Time     `json:"started" yaml:"-"`
+	Finished           *metav1.Time     `json:"finished" yaml:"-"`
+	Timings            StepTimings      `json:"timings" yaml:"-"`
+}
+
+// StepTimings Times at which a step reached each phase of being built and run
+type StepTimings struct {
+	Prepared         *metav1.Time `json:"prepared,omitempty"`
+	ContextStarted   *metav1.Time `json:"contextStarted,omitempty"`
+	ContextFinished  *metav1.Time `json:"contextFinished,omitempty"`
+	ContextFiles     int          `json:"contextFiles,omitempty"`
+	ContextSize      int64        `json:"contextSize,omitempty"`
+	BuildFinished    *metav1.Time `json:"buildFinished,omitempty"`
+	PodRequested     *metav1.Time `json:"podRequested,omitempty"`
+	PodCreated       *metav1.Time `json:"podCreated,omitempty"`
+	ContainerStarted *metav1.Time `json:"containerStarted,omitempty"`
+	Ready            *metav1.Time `json:"ready,omitempty"`
 }
 
 // Port An exposed port
@@ -94,6 +154,16 @@ type Port struct {
 	Internal  string `json:"internal" yaml:"internal"`
 }
 
+// BuildOptions Options for customising the Dockerfile generated for a step. The setup commands are run
+// before the source is copied, so that the layers they create stay cached when only the source changes. Labels
+// aren't part of these options, the labels of a step are given to its image when it's built instead
+type BuildOptions struct {
+	Environment []VariableSource `json:"env" yaml:"env"`
+	Setup       []string         `json:"setup" yaml:"setup"`
+	User        string           `json:"user" yaml:"user"`
+	Workdir     string           `json:"workdir" yaml:"workdir"`
+}
+
 // SourceOptions Source options for a step
 type SourceOptions struct {
 	Dockerignore string   `json:"dockerignore" yaml:"dockerignore"`
@@ -130,7 +200,9 @@ type ExternalStepOptions struct {
 type CompoundStepOptions struct {
 	StepOptions `json:",inline" yaml:",inline"`
 
-	Steps []WorkflowStep `json:"steps" yaml:"steps"`
+	FailFast    *bool          `json:"failFast" yaml:"failFast"`
+	MaxParallel int            `json:"maxParallel" yaml:"maxParallel"`
+	Steps       []WorkflowStep `json:"steps" yaml:"steps"`
 }
 
 // CherryPick Cherry pick a single location
@@ -145,23 +217,48 @@ type ScriptStepOptions struct {
 	StepOptions `json:",inline" yaml:",inline"`
 
 	// CherryPick  []CherryPick     `json:"cherryPick" yaml:"cherryPick"`
+	Build       BuildOptions     `json:"build" yaml:"build"`
+	BuildArgs   []VariableSource `json:"buildArgs" yaml:"buildArgs"`
 	Dockerfile  string           `json:"dockerfile" yaml:"dockerfile"`
 	Environment []VariableSource `json:"environment" yaml:"environment"`
 	Image       string           `json:"image" yaml:"image"`
+	Labels      []VariableSource `json:"labels" yaml:"labels"`
+	NoCache     string           `json:"noCache" yaml:"noCache"`
+	Pull        string           `json:"pull" yaml:"pull"`
 	Script      string           `json:"script" yaml:"script"`
 	Source      SourceOptions    `json:"source" yaml:"source"`
 	Step        string           `json:"step" yaml:"step"`
+	Target      string           `json:"target" yaml:"target"`
 	Volumes     []Volume         `json:"volumes" yaml:"volumes"`
 }
 
+// PublishOptions Options for tagging the image of a step, and pushing it to a registry
+type PublishOptions struct {
+	Password string   `json:"password" yaml:"password"`
+	Push     string   `json:"push" yaml:"push"`
+	Tags     []string `json:"tags" yaml:"tags"`
+	Username string   `json:"username" yaml:"username"`
+}
+
+// PublishStepOptions Options for a publish step, which publishes an existing image or the image of a
+// previous step
+type PublishStepOptions struct {
+	StepOptions    `json:",inline" yaml:",inline"`
+	PublishOptions `json:",inline" yaml:",inline"`
+
+	Image string `json:"image" yaml:"image"`
+	Step  string `json:"step" yaml:"step"`
+}
+
 // ServiceStepOptions Options for a service step
 type ServiceStepOptions struct {
 	ScriptStepOptions `json:",inline" yaml:",inline"`
 
-	Grace     string       `json:"grace" yaml:"grace"`
-	Health    *HealthCheck `json:"health" yaml:"health"`
-	Ports     []Port       `json:"ports" yaml:"ports"`
-	Readiness *HealthCheck `json:"readiness" yaml:"readiness"`
+	Grace     string          `json:"grace" yaml:"grace"`
+	Health    *HealthCheck    `json:"health" yaml:"health"`
+	Ports     []Port          `json:"ports" yaml:"ports"`
+	Publish   *PublishOptions `json:"publish" yaml:"publish"`
+	Readiness *HealthCheck    `json:"readiness" yaml:"readiness"`
 }
 
 // GeneratorStepOptions Options for a generator step
@@ -176,8 +273,9 @@ type GeneratorStepOptions struct {
 type RunStepOptions struct {
 	ScriptStepOptions `json:",inline" yaml:",inline"`
 
-	Cache    string `json:"cache" yaml:"cache"`
-	Parallel string `json:"parallel" yaml:"parallel"`
+	Cache    string          `json:"cache" yaml:"cache"`
+	Parallel string          `json:"parallel" yaml:"parallel"`
+	Publish  *PublishOptions `json:"publish" yaml:"publish"`
 }
 
 // WorkflowStep Step within a workflow
@@ -185,6 +283,7 @@ type WorkflowStep struct {
 	Compound  *CompoundStepOptions  `json:"compound" yaml:"compound"`
 	External  *ExternalStepOptions  `json:"external" yaml:"external"`
 	Generator *GeneratorStepOptions `json:"generator" yaml:"generator"`
+	Publish   *PublishStepOptions   `json:"publish" yaml:"publish"`
 	Run       *RunStepOptions       `json:"run" yaml:"run"`
 	Service   *ServiceStepOptions   `json:"service" yaml:"service"`
 	State     StepState             `json:"state" yaml:"state"`
@@ -208,6 +307,12 @@ const WorkflowWaitDone ChangeType = "workflowWaitDone"
 // StepImageBuilt Image for step has been built
 const StepImageBuilt ChangeType = "stepImageBuilt"
 
+// StepAborted A step failed without the failure being ignored, aborting the workflow
+const StepAborted ChangeType = "stepAborted"
+
+// StepBypassed A skipped step was passed over without being run
+const StepBypassed ChangeType = "stepBypassed"
+
 // ChangeType Type of change
 type ChangeType string
 
@@ -227,6 +332,21 @@ func NewChange(selector []int) *Change {
 	}
 }
 
+// WorkflowStatus Status of a workflow
+type WorkflowStatus string
+
+// WorkflowRunning Workflow is running
+const WorkflowRunning WorkflowStatus = "running"
+
+// WorkflowSucceeded Workflow completed successfully
+const WorkflowSucceeded WorkflowStatus = "succeeded"
+
+// WorkflowFailed Workflow failed
+const WorkflowFailed WorkflowStatus = "failed"
+
+// WorkflowCancelled Workflow was cancelled before it completed
+const WorkflowCancelled WorkflowStatus = "cancelled"
+
 // WorkflowState State of workflow in K8s
 type WorkflowState struct {
 	ID          string                 `json:"id" yaml:"id"`
@@ -234,16 +354,23 @@ type WorkflowState struct {
 	Variables   *properties.Properties `json:"-" yaml:"-"`
 	Changes     []Change               `json:"changes" yaml:"changes"`
 	Step        []int                  `json:"step" yaml:"step"`
+	Status      WorkflowStatus         `json:"status" yaml:"status"`
+	Started     *metav1.Time           `json:"started" yaml:"-"`
+	Finished    *metav1.Time           `json:"finished" yaml:"-"`
+	Pins        map[string]string      `json:"pins,omitempty" yaml:"-"`
 }
 
 // WorkflowSpec Specification of workflow
 type WorkflowSpec struct {
-	State            WorkflowState    `json:"state" yaml:"state"`
-	Steps            []WorkflowStep   `json:"steps" yaml:"steps"`
-	Variables        []VariableSource `json:"variables" yaml:"variables"`
-	IgnoreMissing    bool             `json:"ignoreMissing" yaml:"ignoreMissing"`
-	IgnoreValidation bool             `json:"ignoreValidation" yaml:"ignoreValidation"`
-	IgnoreFailure    bool             `json:"ignoreFailure" yaml:"ignoreFailure"`
+	State            WorkflowState         `json:"state" yaml:"state"`
+	Steps            []WorkflowStep        `json:"steps" yaml:"steps"`
+	Variables        []VariableSource      `json:"variables" yaml:"variables"`
+	IgnoreMissing    bool                  `json:"ignoreMissing" yaml:"ignoreMissing"`
+	IgnoreValidation bool                  `json:"ignoreValidation" yaml:"ignoreValidation"`
+	IgnoreFailure    bool                  `json:"ignoreFailure" yaml:"ignoreFailure"`
+	FailFast         *bool                 `json:"failFast" yaml:"failFast"`
+	MaxParallel      int                   `json:"maxParallel" yaml:"maxParallel"`
+	Registries       []RegistryCredentials `json:"registries" yaml:"registries"`
 }
 
 // Workflow Custom workflow resource
@@ -0,0 +1,46 @@
+package validation
+
+import (
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/errors"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+func validatePublishOptions(step *v1.StepOptions, publish *v1.PublishOptions, selector []int, ignorePlaceholders bool) error {
+	if publish == nil {
+		return nil
+	}
+
+	composite := errors.NewCompositeError()
+
+	if len(publish.Tags) < 1 {
+		composite.Append(newValidationError("At least one tag must be specified to publish the image of " +
+			step.StepName(selector)))
+	}
+
+	if len(publish.Password) > 0 && len(publish.Username) < 1 {
+		composite.Append(newValidationError("A username must be specified along with the password to publish the image of " +
+			step.StepName(selector)))
+	}
+
+	composite.Append(validateFlag(step, publish.Push, "Push", selector, ignorePlaceholders))
+
+	return composite.OrNilIfEmpty()
+}
+
+func validatePublishStep(publish *v1.PublishStepOptions, selector []int, ignorePlaceholders bool) error {
+	composite := errors.NewCompositeError()
+
+	if len(publish.Image) < 1 && len(publish.Step) < 1 {
+		composite.Append(newValidationError("An image or a previous step to publish must be specified for " +
+			publish.StepName(selector)))
+	}
+
+	if len(publish.Image) > 0 && len(publish.Step) > 0 {
+		composite.Append(newValidationError("Both an image and a previous step cannot be published by " +
+			publish.StepName(selector)))
+	}
+
+	composite.Append(validatePublishOptions(&publish.StepOptions, &publish.PublishOptions, selector, ignorePlaceholders))
+
+	return composite.OrNilIfEmpty()
+}
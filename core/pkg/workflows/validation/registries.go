@@ -0,0 +1,26 @@
+package validation
+
+import (
+	"strconv"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/errors"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+func validateRegistries(registries []v1.RegistryCredentials) error {
+	composite := errors.NewCompositeError()
+
+	for i, registry := range registries {
+		registryNumber := strconv.Itoa(i + 1)
+
+		if len(registry.Server) < 1 {
+			composite.Append(newValidationError("A server must be specified for registry " + registryNumber))
+		}
+
+		if len(registry.Username) < 1 {
+			composite.Append(newValidationError("A username must be specified for registry " + registryNumber))
+		}
+	}
+
+	return composite.OrNilIfEmpty()
+}
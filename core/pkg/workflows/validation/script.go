@@ -1,6 +1,8 @@
 package validation
 
 import (
+	"strconv"
+
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/errors"
 	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
 )
@@ -58,17 +60,49 @@ func validateScriptStep(script *v1.ScriptStepOptions, selector []int, ignorePlac
 			script.StepName(selector)))
 	}
 
+	if len(script.Target) > 0 && len(script.Dockerfile) < 1 {
+		composite.Append(newValidationError("A target can only be specified along with a Dockerfile for " +
+			script.StepName(selector)))
+	}
+
+	if len(script.Dockerfile) > 0 && !script.Build.IsEmpty() {
+		composite.Append(newValidationError("Build options cannot be specified along with a Dockerfile for " +
+			script.StepName(selector)))
+	}
+
+	composite.Append(validateFlag(&script.StepOptions, script.NoCache, "No cache", selector, ignorePlaceholders))
+	composite.Append(validatePullPolicy(script, selector, ignorePlaceholders))
 	composite.Append(validateSource(script, selector, ignorePlaceholders))
 
 	return composite.OrNilIfEmpty()
 }
 
+func validatePullPolicy(script *v1.ScriptStepOptions, selector []int, ignorePlaceholders bool) error {
+	if len(script.Pull) < 1 || (ignorePlaceholders && containsPlaceholders(script.Pull)) {
+		return nil
+	}
+
+	switch v1.PullPolicy(script.Pull) {
+	case v1.PullAlways, v1.PullIfNotPresent, v1.PullNever:
+		return nil
+	}
+
+	_, err := strconv.ParseBool(script.Pull)
+	if err != nil {
+		return newValidationError("Pull policy must be one of always, ifNotPresent or never in step " +
+			script.StepName(selector))
+	}
+
+	return nil
+}
+
 func validateRunStep(run *v1.RunStepOptions, selector []int, ignorePlaceholders bool) error {
 	composite := errors.NewCompositeError()
 
 	composite.Append(validateScriptStep(&run.ScriptStepOptions, selector, ignorePlaceholders))
 	composite.Append(validateFlag(&run.StepOptions, run.Cache, "Cache", selector, ignorePlaceholders))
 	composite.Append(validateFlag(&run.StepOptions, run.Parallel, "Parallel", selector, ignorePlaceholders))
+	composite.Append(validatePublishOptions(&run.StepOptions, run.Publish, selector, ignorePlaceholders))
 
 	return composite.OrNilIfEmpty()
 }
@@ -71,10 +71,28 @@ func validateHealthCheck(service *v1.ServiceStepOptions, checkName string, check
 	return nil
 }
 
+func validateGrace(service *v1.ServiceStepOptions, selector []int, ignorePlaceholders bool) error {
+	if len(service.Grace) > 0 {
+		if ignorePlaceholders && containsPlaceholders(service.Grace) {
+			return nil
+		}
+
+		v, err := strconv.ParseInt(service.Grace, 10, 64)
+		if err != nil || v < 0 {
+			return newValidationError("Grace must be a number of seconds in service step " +
+				service.StepName(selector))
+		}
+	}
+
+	return nil
+}
+
 func validateServiceStep(service *v1.ServiceStepOptions, selector []int, ignorePlaceholders bool) error {
 	composite := errors.NewCompositeError()
 
 	composite.Append(validateScriptStep(&service.ScriptStepOptions, selector, ignorePlaceholders))
+	composite.Append(validateGrace(service, selector, ignorePlaceholders))
+	composite.Append(validatePublishOptions(&service.StepOptions, service.Publish, selector, ignorePlaceholders))
 
 	if service.Readiness != nil {
 		composite.Append(validateHealthCheck(service, "readiness", service.Readiness, selector, ignorePlaceholders))
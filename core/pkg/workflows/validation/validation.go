@@ -65,6 +65,10 @@ func validateStepType(step *v1.WorkflowStep, stepSelector []int, ignorePlacehold
 		types++
 	}
 
+	if step.Publish != nil {
+		types++
+	}
+
 	if types > 1 {
 		return newValidationError("Only a single type can be specified for " + step.StepName(stepSelector))
 	} else if types < 1 {
@@ -74,8 +78,17 @@ func validateStepType(step *v1.WorkflowStep, stepSelector []int, ignorePlacehold
 	return nil
 }
 
+func validateMaxParallel(maxParallel int, description string) error {
+	if maxParallel < 0 {
+		return newValidationError("Max parallel must not be negative in " + description)
+	}
+
+	return nil
+}
+
 func validateCompoundStep(compound *v1.CompoundStepOptions, selector []int) error {
 	composite := errors.NewCompositeError()
+	composite.Append(validateMaxParallel(compound.MaxParallel, "step "+compound.StepName(selector)))
 
 	for stepNumber, subStep := range compound.Steps {
 		subStepSelector := append(selector, stepNumber)
@@ -101,6 +114,8 @@ func validateStepInternal(step *v1.WorkflowStep, selector []int, ignorePlacehold
 		return validateGeneratorStep(step.Generator, selector, ignorePlaceholders)
 	} else if step.Compound != nil {
 		return validateCompoundStep(step.Compound, selector)
+	} else if step.Publish != nil {
+		return validatePublishStep(step.Publish, selector, ignorePlaceholders)
 	}
 
 	return nil
@@ -117,6 +132,16 @@ func Validate(workflowSpec *v1.WorkflowSpec) error {
 		return newValidationError("Workflow must contain at least 1 step!")
 	}
 
+	err := validateMaxParallel(workflowSpec.MaxParallel, "workflow")
+	if err != nil {
+		return err
+	}
+
+	err = validateRegistries(workflowSpec.Registries)
+	if err != nil {
+		return err
+	}
+
 	stepSelector := make([]int, 1, 2)
 	for stepNumber, step := range workflowSpec.Steps {
 		stepSelector[0] = stepNumber
@@ -0,0 +1,116 @@
+package watch
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/execution/image"
+	imagecontext "github.com/stackfoundation/sandbox/core/pkg/workflows/image"
+	"github.com/stackfoundation/sandbox/core/pkg/workflows/v1"
+)
+
+func isWithin(directory string, path string) bool {
+	relative, err := filepath.Rel(directory, path)
+	return err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator))
+}
+
+// IncludesWorkflows Do any of the changed files, relative to the project root, fall within the specified
+// workflows directory?
+func IncludesWorkflows(projectRoot string, workflowsDirectory string, changes []string) bool {
+	for _, change := range changes {
+		if isWithin(workflowsDirectory, filepath.Join(projectRoot, change)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// VolumePaths Get the host paths of the volumes of the steps of a workflow, which the steps can write their
+// output to
+func VolumePaths(workflow *v1.Workflow, projectRoot string) []string {
+	var paths []string
+
+	selector := workflow.IncrementStepSelector([]int{})
+	for len(selector) > 0 {
+		for _, volume := range workflow.Select(selector).Volumes() {
+			if len(volume.HostPath) > 0 {
+				paths = append(paths, filepath.Join(projectRoot, volume.HostPath))
+			}
+		}
+
+		selector = workflow.IncrementStepSelector(selector)
+	}
+
+	return paths
+}
+
+// WithoutOutputs Get the changed files, relative to the project root, which don't fall within any of the
+// specified output paths, which are written by workflow runs rather than changed by the user
+func WithoutOutputs(projectRoot string, changes []string, outputs []string) []string {
+	var filtered []string
+
+	for _, change := range changes {
+		path := filepath.Join(projectRoot, change)
+
+		output := false
+		for _, outputPath := range outputs {
+			if isWithin(outputPath, path) {
+				output = true
+				break
+			}
+		}
+
+		if !output {
+			filtered = append(filtered, change)
+		}
+	}
+
+	return filtered
+}
+
+func contextChanged(workflow *v1.Workflow, step *v1.WorkflowStep, changes []string) (bool, error) {
+	excludes, err := image.StepContextExcludes(&workflow.Spec, step)
+	if err != nil {
+		return false, err
+	}
+
+	for _, change := range changes {
+		excluded, err := imagecontext.Matches(change, excludes)
+		if err != nil {
+			return false, err
+		}
+
+		if !excluded {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ChangedSteps Get the paths of the steps of a workflow whose image build context includes any of the
+// changed files, relative to the project root
+func ChangedSteps(workflow *v1.Workflow, changes []string) ([]string, error) {
+	var changed []string
+
+	selector := workflow.IncrementStepSelector([]int{})
+	for len(selector) > 0 {
+		step := workflow.Select(selector)
+
+		if step.RequiresBuild() && !step.IsSkipped() {
+			stepChanged, err := contextChanged(workflow, step, changes)
+			if err != nil {
+				return nil, err
+			}
+
+			if stepChanged {
+				changed = append(changed, workflow.StepPath(selector))
+			}
+		}
+
+		selector = workflow.IncrementStepSelector(selector)
+	}
+
+	return changed, nil
+}
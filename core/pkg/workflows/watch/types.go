@@ -0,0 +1,16 @@
+package watch
+
+import (
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher Watches the files within a directory for changes
+type Watcher struct {
+	changes  chan []string
+	closed   chan bool
+	debounce time.Duration
+	root     string
+	watcher  *fsnotify.Watcher
+}
@@ -0,0 +1,132 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/stackfoundation/sandbox/log"
+)
+
+// NewWatcher Create a watcher which watches all the files within the specified directory. Changes are
+// reported once no further changes have been made for the specified debounce period
+func NewWatcher(root string, debounce time.Duration) (*Watcher, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	watcher := &Watcher{
+		changes:  make(chan []string),
+		closed:   make(chan bool),
+		debounce: debounce,
+		root:     root,
+		watcher:  fsWatcher,
+	}
+
+	err = watcher.addDirectories(root)
+	if err != nil {
+		fsWatcher.Close()
+		return nil, err
+	}
+
+	go watcher.watch()
+	return watcher, nil
+}
+
+// Changes Get the channel on which batches of changed files, relative to the watched directory, are sent
+func (w *Watcher) Changes() <-chan []string {
+	return w.changes
+}
+
+// Close Stop watching for changes
+func (w *Watcher) Close() error {
+	close(w.closed)
+	return w.watcher.Close()
+}
+
+func (w *Watcher) addDirectories(directory string) error {
+	return filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+
+			return w.watcher.Add(path)
+		}
+
+		return nil
+	})
+}
+
+func (w *Watcher) addIfDirectory(path string) {
+	info, err := os.Stat(path)
+	if err == nil && info.IsDir() {
+		err = w.addDirectories(path)
+		if err != nil {
+			log.Debugf("Unable to watch %v for changes: %v", path, err.Error())
+		}
+	}
+}
+
+func sortedChanges(pending map[string]bool) []string {
+	changes := make([]string, 0, len(pending))
+	for change := range pending {
+		changes = append(changes, change)
+	}
+
+	sort.Strings(changes)
+	return changes
+}
+
+func (w *Watcher) watch() {
+	pending := make(map[string]bool)
+	var debounced <-chan time.Time
+
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+
+			if event.Op&fsnotify.Create == fsnotify.Create {
+				w.addIfDirectory(event.Name)
+			}
+
+			relative, err := filepath.Rel(w.root, event.Name)
+			if err == nil {
+				pending[relative] = true
+				debounced = time.After(w.debounce)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+
+			log.Debugf("Error watching for changes: %v", err.Error())
+		case <-debounced:
+			debounced = nil
+			changes := sortedChanges(pending)
+			pending = make(map[string]bool)
+
+			select {
+			case w.changes <- changes:
+			case <-w.closed:
+				return
+			}
+		case <-w.closed:
+			return
+		}
+	}
+}
@@ -1,22 +1,41 @@
 package log
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
 
 var debug bool
 
+var output io.Writer = os.Stdout
+
 // JsonOutput Is JSON output enabled?
 var JSONOutput bool
 
+type jsonMessage struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func printJSON(code string, message string, arguments ...interface{}) {
+	content, err := json.Marshal(&jsonMessage{
+		Code:    code,
+		Message: fmt.Sprintf(message, arguments...),
+	})
+	if err == nil {
+		fmt.Fprintln(output, string(content))
+	}
+}
+
 // Error Log error output
 func Error(code string, message string, arguments ...interface{}) {
 	if JSONOutput {
-		fmt.Printf("{\"code\":\"%v\",\"message\":\"", code)
-		fmt.Printf(message, arguments...)
-		fmt.Printf("\"}")
-		fmt.Println()
+		printJSON(code, message, arguments...)
 	} else {
-		fmt.Printf(message, arguments...)
-		fmt.Println()
+		fmt.Fprintf(output, message, arguments...)
+		fmt.Fprintln(output)
 	}
 }
 
@@ -28,13 +47,10 @@ func Errorf(message string, arguments ...interface{}) {
 func Debug(code string, message string, arguments ...interface{}) {
 	if debug || JSONOutput {
 		if JSONOutput {
-			fmt.Printf("{\"code\":\"%v\",\"message\":\"", code)
-			fmt.Printf(message, arguments...)
-			fmt.Printf("\"}")
-			fmt.Println()
+			printJSON(code, message, arguments...)
 		} else {
-			fmt.Printf(message, arguments...)
-			fmt.Println()
+			fmt.Fprintf(output, message, arguments...)
+			fmt.Fprintln(output)
 		}
 	}
 }
@@ -48,7 +64,23 @@ func Debugf(message string, arguments ...interface{}) {
 	Debug("", message, arguments...)
 }
 
+// Infof Log informational output, meant for people rather than programs
+func Infof(message string, arguments ...interface{}) {
+	fmt.Fprintf(output, message, arguments...)
+	fmt.Fprintln(output)
+}
+
+// Output Get the writer to which all output is logged
+func Output() io.Writer {
+	return output
+}
+
 // SetDebug Set debug mode on or off
 func SetDebug(isDebug bool) {
 	debug = isDebug
 }
+
+// SetOutput Set the writer to which all output is logged
+func SetOutput(writer io.Writer) {
+	output = writer
+}
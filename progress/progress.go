@@ -1,6 +1,7 @@
 package progress
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"time"
@@ -14,6 +15,13 @@ func timestamp() int64 {
 	return time.Now().UnixNano() / int64(time.Millisecond)
 }
 
+type progressMessage struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Current int64  `json:"current"`
+	Total   int64  `json:"total"`
+}
+
 type progressAwareReader struct {
 	io.Reader
 	title        string
@@ -34,9 +42,15 @@ func (reader *progressAwareReader) Read(p []byte) (int, error) {
 		reader.lastProgress = now
 
 		if log.JSONOutput {
-			fmt.Printf("{\"code\":\"%v\",\"message\":\"%v\",\"current\":%v,\"total\":%v}",
-				reader.code, reader.title, reader.current, reader.total)
-			fmt.Println()
+			content, err := json.Marshal(&progressMessage{
+				Code:    reader.code,
+				Message: reader.title,
+				Current: reader.current,
+				Total:   reader.total,
+			})
+			if err == nil {
+				fmt.Println(string(content))
+			}
 		} else {
 			fmt.Printf("%v [", reader.title)
 			position := int(barWidth * (float64(reader.current) / float64(reader.total)))